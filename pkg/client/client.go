@@ -0,0 +1,322 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package client provides a client of Non-K8s API of the hub of hubs, which serves the resources reported by
+// all the leaf hubs, e.g. managed clusters and policies.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const nonK8sAPIPath = "multicloud/hub-of-hubs-nonk8s-api"
+
+var (
+	errNoURL          = errors.New("the URL of Non-K8s API must be specified")
+	errStatusNotOK    = errors.New("response status not HTTP OK")
+	errNoCertificates = errors.New("no certificates found")
+)
+
+// Config holds the configuration of a client of Non-K8s API.
+type Config struct {
+	// URL is the URL of Non-K8s API, e.g. https://multicloud-console.apps.example.com
+	URL string
+	// BearerToken authorizes the requests
+	BearerToken string
+	// CAFile is the file of the certificate authorities verifying the certificate of the server, in addition to
+	// the certificate authorities of the system. Ignored if Transport is set.
+	CAFile string
+	// CAData holds the certificate authorities verifying the certificate of the server if CAFile is empty, e.g.
+	// the certificate-authority-data of the kubeconfig. Ignored if Transport is set.
+	CAData []byte
+	// InsecureSkipTLSVerify disables the verification of the certificate of the server. Ignored if Transport is
+	// set.
+	InsecureSkipTLSVerify bool
+	// Timeout is the time to wait for the response headers of the server, no limit if zero. Ignored if Transport
+	// is set.
+	Timeout time.Duration
+	// Transport sends the requests. If nil, a transport configured by the TLS fields and Timeout is used.
+	Transport http.RoundTripper
+	// Retries is the number of times the GET requests are retried on transient failures
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubled for each next retry. Defaults to
+	// DefaultRetryBackoff.
+	RetryBackoff time.Duration
+	// WrapTransport, if not nil, wraps the transport, e.g. to log, trace or retry the requests
+	WrapTransport func(rt http.RoundTripper) http.RoundTripper
+}
+
+// Client is a client of Non-K8s API.
+type Client struct {
+	httpClient *http.Client
+	url        string
+	token      string
+}
+
+// StatusError is returned if Non-K8s API responds with an unsuccessful status.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%v: %d: %s", errStatusNotOK, e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("%v: %d", errStatusNotOK, e.StatusCode)
+}
+
+// IsNotFound returns true if the error is a StatusError with the status Not Found.
+func IsNotFound(err error) bool {
+	var statusError *StatusError
+
+	return errors.As(err, &statusError) && statusError.StatusCode == http.StatusNotFound
+}
+
+// New returns a client for the config.
+func New(config *Config) (*Client, error) {
+	if config.URL == "" {
+		return nil, errNoURL
+	}
+
+	transport := config.Transport
+	if transport == nil {
+		tlsConfig, err := newTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		transport = &http.Transport{
+			TLSClientConfig:       tlsConfig,
+			ResponseHeaderTimeout: config.Timeout,
+		}
+	}
+
+	if config.Retries > 0 {
+		transport = newRetryTransport(transport, config.Retries, config.RetryBackoff)
+	}
+
+	if config.WrapTransport != nil {
+		transport = config.WrapTransport(transport)
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Transport:     transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		url:   strings.TrimSuffix(config.URL, "/"),
+		token: config.BearerToken,
+	}, nil
+}
+
+// NewForConfigFlags returns a client for the current context of the kubeconfig of the config flags, configured by
+// the settings of the context in the config file of the plugin.
+func NewForConfigFlags(configFlags *genericclioptions.ConfigFlags) (*Client, error) {
+	contextName, err := pluginconfig.CurrentContext(configFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewForContext(configFlags, contextName)
+}
+
+// NewForContext returns a client for the context of the kubeconfig of the config flags, configured by the settings
+// of the context in the config file of the plugin and by --insecure-skip-tls-verify.
+func NewForContext(configFlags *genericclioptions.ConfigFlags, contextName string) (*Client, error) {
+	kubeconfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig.CurrentContext = contextName
+
+	pluginConfig, err := pluginconfig.Load(pluginconfig.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ConfigForSettings(kubeconfig, pluginConfig.SettingsFor(contextName))
+	if err != nil {
+		return nil, err
+	}
+
+	if configFlags.Insecure != nil && *configFlags.Insecure {
+		config.InsecureSkipTLSVerify = true
+	}
+
+	return New(config)
+}
+
+// ConfigForSettings returns the config of a client for the current context of the kubeconfig, as returned by
+// ConfigForKubeconfig, with the settings of the context in the config file of the plugin applied.
+func ConfigForSettings(kubeconfig clientcmdapi.Config, settings *pluginconfig.Settings) (*Config, error) {
+	var config *Config
+
+	if settings.URL != "" {
+		token, err := pluginutil.Token(kubeconfig, kubeconfig.CurrentContext)
+		if err != nil {
+			return nil, err
+		}
+
+		cluster, err := pluginutil.Cluster(kubeconfig, kubeconfig.CurrentContext)
+		if err != nil {
+			return nil, err
+		}
+
+		config = &Config{URL: settings.URL, BearerToken: token}
+		setCertificateAuthority(config, cluster)
+	} else {
+		var err error
+		if config, err = ConfigForKubeconfig(kubeconfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.CAFile != "" {
+		config.CAFile, config.CAData = settings.CAFile, nil
+	}
+
+	if settings.InsecureSkipTLSVerify {
+		config.InsecureSkipTLSVerify = true
+	}
+
+	config.Retries = settings.Retries
+
+	if settings.Timeout != nil {
+		config.Timeout = settings.Timeout.Duration
+	}
+
+	if settings.RetryBackoff != nil {
+		config.RetryBackoff = settings.RetryBackoff.Duration
+	}
+
+	return config, nil
+}
+
+// ConfigForKubeconfig returns the config of a client for the current context of the kubeconfig. The URL of
+// Non-K8s API is derived from the URL of the API server of the hub of hubs, the requests are authorized by
+// the token of the user, and the certificate of Non-K8s API is verified by the certificate authority of the hub
+// of hubs, as for its API server.
+func ConfigForKubeconfig(kubeconfig clientcmdapi.Config) (*Config, error) {
+	nonk8sAPIURL, err := pluginutil.NonK8sAPIURL(kubeconfig, kubeconfig.CurrentContext)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := pluginutil.Token(kubeconfig, kubeconfig.CurrentContext)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := pluginutil.Cluster(kubeconfig, kubeconfig.CurrentContext)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{URL: nonk8sAPIURL, BearerToken: token}
+	setCertificateAuthority(config, cluster)
+
+	return config, nil
+}
+
+// setCertificateAuthority sets the TLS fields of the config to the certificate authority of the cluster of the
+// kubeconfig, including its insecure-skip-tls-verify
+func setCertificateAuthority(config *Config, cluster *clientcmdapi.Cluster) {
+	config.CAFile = cluster.CertificateAuthority
+	config.CAData = cluster.CertificateAuthorityData
+	config.InsecureSkipTLSVerify = cluster.InsecureSkipTLSVerify
+}
+
+// URL returns the URL of Non-K8s API.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// ResourceURL returns the URL of the resource path in Non-K8s API.
+func (c *Client) ResourceURL(resourcePath string) string {
+	return fmt.Sprintf("%s/%s/%s", c.url, nonK8sAPIPath, resourcePath)
+}
+
+// NewRequest returns a request for the resource path in Non-K8s API, authorized by the token.
+func (c *Client) NewRequest(ctx context.Context, method, resourcePath string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.ResourceURL(resourcePath), body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+
+	return req, nil
+}
+
+// Do sends the request and returns the response body, failing with a StatusError on unsuccessful statuses.
+func (c *Client) Do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("got error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	return body, nil
+}
+
+// newTLSConfig returns the TLS config verifying the certificate of the server by the certificate authorities of the
+// system and of the config, unless the config skips the verification
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	if config.InsecureSkipTLSVerify {
+		return &tls.Config{
+			//nolint:gosec
+			InsecureSkipVerify: true,
+		}, nil
+	}
+
+	caData, source := config.CAData, "the certificate authority data"
+	if config.CAFile != "" {
+		var err error
+		if caData, err = ioutil.ReadFile(config.CAFile); err != nil {
+			return nil, fmt.Errorf("unable to read the CA file: %w", err)
+		}
+
+		source = "the CA file " + config.CAFile
+	}
+
+	if len(caData) == 0 {
+		return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if !rootCAs.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("%w: in %s", errNoCertificates, source)
+	}
+
+	return &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package client
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Factory returns the clients of Non-K8s API of the commands: clients for the kubeconfig of the config flags, or
+// for a fixed config, e.g. of a fake Non-K8s API.
+type Factory struct {
+	configFlags *genericclioptions.ConfigFlags
+	config      *Config
+}
+
+// NewFactory returns a factory of clients for the kubeconfig of the config flags.
+func NewFactory(configFlags *genericclioptions.ConfigFlags) *Factory {
+	return &Factory{configFlags: configFlags}
+}
+
+// ConfigFlags returns the config flags of the kubeconfig.
+func (f *Factory) ConfigFlags() *genericclioptions.ConfigFlags {
+	return f.configFlags
+}
+
+// SetConfig makes the factory return clients for the config instead of the kubeconfig, e.g. to run the commands
+// against a fake Non-K8s API. A nil config removes the config.
+func (f *Factory) SetConfig(config *Config) {
+	f.config = config
+}
+
+// IsFixed returns true if the clients are for the config set by SetConfig, e.g. of a fake Non-K8s API, whose
+// objects must not be cached.
+func (f *Factory) IsFixed() bool {
+	return f.config != nil
+}
+
+// NewClient returns a client for the current context of the kubeconfig, as returned by NewForConfigFlags, or for
+// the config set by SetConfig.
+func (f *Factory) NewClient() (*Client, error) {
+	if f.config != nil {
+		return New(f.config)
+	}
+
+	return NewForConfigFlags(f.configFlags)
+}
+
+// NewClientForContext returns a client for the context of the kubeconfig, as returned by NewForContext, or for the
+// config set by SetConfig.
+func (f *Factory) NewClientForContext(contextName string) (*Client, error) {
+	if f.config != nil {
+		return New(f.config)
+	}
+
+	return NewForContext(f.configFlags, contextName)
+}
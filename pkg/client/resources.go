@@ -0,0 +1,232 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	// ManagedClustersPath is the path of managed clusters in Non-K8s API
+	ManagedClustersPath = "managedclusters"
+
+	kubeconfigSubresource = "kubeconfig"
+)
+
+var (
+	// ErrNotFound is returned by Get if there is no object with the name
+	ErrNotFound = errors.New("not found")
+	// ErrDiscoveryNotSupported is returned if Non-K8s API does not serve the discovery endpoint
+	ErrDiscoveryNotSupported = errors.New("discovery is not supported by Non-K8s API")
+)
+
+// List returns the objects of the resource path. The items of lists returned by Non-K8s API are returned
+// as objects, and lists returned in pages are followed by their continue tokens.
+func (c *Client) List(ctx context.Context, resourcePath string) ([]*unstructured.Unstructured, error) {
+	var results []*unstructured.Unstructured
+
+	path := resourcePath
+
+	for {
+		body, err := c.GetRaw(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+
+		objs, err := pluginutil.GetObjects(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get objects from the body: %w", err)
+		}
+
+		continueToken := ""
+
+		for _, obj := range objs {
+			switch typedObj := obj.(type) {
+			case *unstructured.Unstructured:
+				results = append(results, typedObj)
+			case *unstructured.UnstructuredList:
+				for i := range typedObj.Items {
+					results = append(results, &typedObj.Items[i])
+				}
+				continueToken = typedObj.GetContinue()
+			}
+		}
+
+		if continueToken == "" {
+			return results, nil
+		}
+
+		path = fmt.Sprintf("%s?continue=%s", resourcePath, url.QueryEscape(continueToken))
+	}
+}
+
+// Get returns the object of the resource path with the namespace and the name. The namespace is empty for
+// cluster-scoped resources. Non-K8s API serves only the lists of the resources, so the object is picked from
+// the list, failing with ErrNotFound if it is not in the list.
+func (c *Client) Get(ctx context.Context, resourcePath, namespace, name string) (*unstructured.Unstructured, error) {
+	objs, err := c.List(ctx, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objs {
+		if obj.GetNamespace() == namespace && obj.GetName() == name {
+			return obj, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %q", ErrNotFound, resourcePath, name)
+}
+
+// GetRaw returns the body returned by Non-K8s API for the resource path, as JSON.
+func (c *Client) GetRaw(ctx context.Context, resourcePath string) ([]byte, error) {
+	req, err := c.NewRequest(ctx, "GET", resourcePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	return c.Do(req)
+}
+
+// Patch patches the object of the resource path with the namespace and the name, and returns the patched object
+// if Non-K8s API returned it, or else nil. The namespace is empty for cluster-scoped resources.
+func (c *Client) Patch(ctx context.Context, resourcePath, namespace, name string, patchType types.PatchType,
+	data []byte) (*unstructured.Unstructured, error) {
+	req, err := c.NewRequest(ctx, "PATCH", objectPath(resourcePath, namespace, name), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", string(patchType))
+	req.Header.Add("Accept", "application/json")
+
+	body, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if objs, err := pluginutil.GetObjects(body); err == nil && len(objs) == 1 {
+		if obj, ok := objs[0].(*unstructured.Unstructured); ok {
+			return obj, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Delete deletes the object of the resource path with the namespace and the name. The namespace is empty for
+// cluster-scoped resources.
+func (c *Client) Delete(ctx context.Context, resourcePath, namespace, name string) error {
+	req, err := c.NewRequest(ctx, "DELETE", objectPath(resourcePath, namespace, name), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req)
+
+	return err
+}
+
+// objectPath returns the path of the object of the resource path with the name, in the namespace if not empty
+func objectPath(resourcePath, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", resourcePath, name)
+	}
+
+	return fmt.Sprintf("%s/namespaces/%s/%s", resourcePath, namespace, name)
+}
+
+// ListManagedClusters returns the managed clusters reported by all the leaf hubs.
+func (c *Client) ListManagedClusters(ctx context.Context) ([]*clusterv1.ManagedCluster, error) {
+	objs, err := c.List(ctx, ManagedClustersPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return pluginutil.ToManagedClusters(toObjects(objs))
+}
+
+// GetManagedCluster returns the managed cluster with the name.
+func (c *Client) GetManagedCluster(ctx context.Context, name string) (*clusterv1.ManagedCluster, error) {
+	obj, err := c.Get(ctx, ManagedClustersPath, "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	managedClusters, err := pluginutil.ToManagedClusters(toObjects([]*unstructured.Unstructured{obj}))
+	if err != nil {
+		return nil, err
+	}
+
+	return managedClusters[0], nil
+}
+
+// GetManagedClusterKubeconfig returns a kubeconfig for the managed cluster, which Non-K8s API gets or mints on
+// the leaf hub of the managed cluster.
+func (c *Client) GetManagedClusterKubeconfig(ctx context.Context, name string) (*clientcmdapi.Config, error) {
+	req, err := c.NewRequest(ctx, "GET", fmt.Sprintf("%s/%s/%s", ManagedClustersPath, name, kubeconfigSubresource),
+		nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/yaml, application/json")
+
+	body, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get kubeconfig of %s: %w", name, err)
+	}
+
+	config, err := clientcmd.Load(body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse kubeconfig of %s: %w", name, err)
+	}
+
+	return config, nil
+}
+
+// GetAPIResources returns the resources served by Non-K8s API, as lists of the resources of each group version,
+// from the discovery endpoint at the discovery path. It fails with ErrDiscoveryNotSupported if Non-K8s API
+// does not serve the discovery endpoint.
+func (c *Client) GetAPIResources(ctx context.Context, discoveryPath string) ([]metav1.APIResourceList, error) {
+	body, err := c.GetRaw(ctx, discoveryPath)
+	if IsNotFound(err) {
+		return nil, ErrDiscoveryNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResourceLists []metav1.APIResourceList
+	if err := json.Unmarshal(body, &apiResourceLists); err != nil {
+		return nil, fmt.Errorf("unable to decode the API resources: %w", err)
+	}
+
+	return apiResourceLists, nil
+}
+
+func toObjects(objs []*unstructured.Unstructured) []runtime.Object {
+	results := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		results = append(results, obj)
+	}
+
+	return results
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package client
+
+import (
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultRetryBackoff is the delay before the first retry of a request if none is configured
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// transientStatusCodes are the statuses of the failures of Non-K8s API worth retrying
+var transientStatusCodes = sets.NewInt(http.StatusTooManyRequests, http.StatusBadGateway,
+	http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+
+// IsRetryable returns true if a request that failed with the error may succeed when sent again: unsuccessful
+// statuses other than the transient ones, e.g. Unauthorized or Forbidden, and failures to verify the certificate of
+// the server are not retryable.
+func IsRetryable(err error) bool {
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return transientStatusCodes.Has(statusError.StatusCode)
+	}
+
+	var (
+		unknownAuthorityError   x509.UnknownAuthorityError
+		hostnameError           x509.HostnameError
+		certificateInvalidError x509.CertificateInvalidError
+	)
+
+	return !errors.As(err, &unknownAuthorityError) && !errors.As(err, &hostnameError) &&
+		!errors.As(err, &certificateInvalidError)
+}
+
+// retryTransport retries the GET requests on connection errors and transient failures, waiting for a backoff
+// doubled after each retry. The other requests are not retried, since they may not be idempotent.
+type retryTransport struct {
+	delegate http.RoundTripper
+	retries  int
+	backoff  time.Duration
+}
+
+func newRetryTransport(delegate http.RoundTripper, retries int, backoff time.Duration) *retryTransport {
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	return &retryTransport{delegate: delegate, retries: retries, backoff: backoff}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.backoff
+
+	for retry := 0; ; retry++ {
+		resp, err := t.delegate.RoundTrip(req)
+		if req.Method != http.MethodGet || retry >= t.retries || req.Context().Err() != nil ||
+			(err == nil && !transientStatusCodes.Has(resp.StatusCode)) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// drain the body so that the connection is reused
+			//nolint:errcheck
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
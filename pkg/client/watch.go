@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var (
+	// ErrWatchNotSupported is returned if Non-K8s API does not serve a watch stream for a resource
+	ErrWatchNotSupported    = errors.New("watch is not supported")
+	errUnexpectedWatchEvent = errors.New("unexpected watch event")
+)
+
+// WatchEvent is an event of the watch stream of Non-K8s API
+type WatchEvent struct {
+	Type   watch.EventType            `json:"type"`
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+// Watch calls the handler with the added, modified and deleted events of the watch stream of the resource
+// path, until the handler returns true or an error, the stream ends or the context is done.
+// It returns whether the handler returned true, or ErrWatchNotSupported if there is no watch stream for the resource,
+// i.e. the status is Not Found, Method Not Allowed or Not Implemented. Other unsuccessful statuses, e.g. Unauthorized,
+// are returned as a StatusError, and a stream that is not of watch events fails with the decoding error.
+// A stream ended by the server, even before any event, returns false and no error, so the caller can watch again.
+func (c *Client) Watch(ctx context.Context, resourcePath string,
+	handler func(*WatchEvent) (bool, error)) (bool, error) {
+	req, err := c.NewRequest(ctx, "GET", resourcePath+"?watch=true", nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("got error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return false, fmt.Errorf("%w: %d", ErrWatchNotSupported, resp.StatusCode)
+	default:
+		body, _ := ioutil.ReadAll(resp.Body)
+		return false, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		event := &WatchEvent{}
+		err := decoder.Decode(event)
+		if err == nil && event.Object == nil {
+			err = errUnexpectedWatchEvent
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			// an accepted watch whose stream ended, even before any event, is not a failure
+			if errors.Is(err, io.EOF) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+		case watch.Bookmark:
+			continue
+		default:
+			return false, fmt.Errorf("%w: %s", errUnexpectedWatchEvent, event.Type)
+		}
+
+		if done, err := handler(event); done || err != nil {
+			return done, err
+		}
+	}
+}
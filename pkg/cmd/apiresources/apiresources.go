@@ -0,0 +1,337 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package apiresources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	apiResourcesLong = templates.LongDesc(i18n.T(`
+		Print the supported API resources of Non-K8s API of the hub of hubs.
+
+		The resources are discovered from Non-K8s API and cached per kubeconfig context and
+		URL of Non-K8s API for 10 minutes, except with --fake. The other commands accept the
+		discovered resources too. If Non-K8s API does not support discovery or cannot be
+		reached, the resources built into the plugin are printed.`))
+
+	apiResourcesExample = templates.Examples(i18n.T(`
+		# Print the supported API resources
+		%[1]s api-resources
+
+		# Print the supported API resources with more information, including their verbs
+		%[1]s api-resources -o wide
+
+		# Print the supported API resources sorted by a column
+		%[1]s api-resources --sort-by=name
+
+		# Print the supported namespaced resources
+		%[1]s api-resources --namespaced=true
+
+		# Print the supported API resources with a specific APIGroup
+		%[1]s api-resources --api-group=policy.open-cluster-management.io
+
+		# Print the supported API resources from the cache, even if it expired
+		%[1]s api-resources --cached`))
+
+	errInvalidOutput = errors.New("invalid output format")
+	errInvalidSortBy = errors.New("invalid --sort-by")
+)
+
+const (
+	wideOutput = "wide"
+	nameOutput = "name"
+
+	sortByName = "name"
+	sortByKind = "kind"
+
+	// discoveryCacheDir is the directory of the discovery cache in the cache directory of kubectl
+	discoveryCacheDir = "mcl/discovery"
+)
+
+// Options contains the input to the api-resources command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	Output     string
+	SortBy     string
+	APIGroup   string
+	Namespaced bool
+	Verbs      []string
+	NoHeaders  bool
+	Cached     bool
+
+	namespacedSet    bool
+	discoveryPath    string
+	resourceRegistry *registry.Registry
+}
+
+// NewOptions returns an Options for the api-resources command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry, discoveryPath string) *Options {
+	return &Options{
+		Namespaced: true,
+
+		configFlags:      clientFactory.ConfigFlags(),
+		clientFactory:    clientFactory,
+		IOStreams:        streams,
+		resourceRegistry: resourceRegistry,
+		discoveryPath:    discoveryPath,
+	}
+}
+
+// NewCmd creates a command object for the "api-resources" action, which prints the resources served by
+// Non-K8s API. The discovery path is the path of the discovery endpoint in Non-K8s API, the registry holds
+// the built-in resources.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry, discoveryPath string) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourceRegistry, discoveryPath)
+
+	cmd := &cobra.Command{
+		Use:                   "api-resources",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Print the supported API resources of Non-K8s API"),
+		Long:                  apiResourcesLong,
+		Example:               fmt.Sprintf(apiResourcesExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When using the default or custom-column output format, don't print headers (default print headers).")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: wide|name.")
+	cmd.Flags().StringVar(&o.APIGroup, "api-group", o.APIGroup, "Limit to resources in the specified API group.")
+	cmd.Flags().BoolVar(&o.Namespaced, "namespaced", o.Namespaced, "If false, non-namespaced resources will be returned, otherwise returning namespaced resources by default.")
+	cmd.Flags().StringSliceVar(&o.Verbs, "verbs", o.Verbs, "Limit to resources that support the specified verbs.")
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "If non-empty, sort list of resources using specified field. The field can be either 'name' or 'kind'.")
+	cmd.Flags().BoolVar(&o.Cached, "cached", o.Cached, "Use the cached list of resources if available, even if it expired.")
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Values(wideOutput, nameOutput)))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("sort-by", completion.Values(sortByName, sortByKind)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	o.namespacedSet = cmd.Flags().Changed("namespaced")
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	switch o.Output {
+	case "", wideOutput, nameOutput:
+	default:
+		return fmt.Errorf("%w: %q, must be one of wide|name", errInvalidOutput, o.Output)
+	}
+
+	switch o.SortBy {
+	case "", sortByName, sortByKind:
+	default:
+		return fmt.Errorf("%w: %q, must be one of name|kind", errInvalidSortBy, o.SortBy)
+	}
+
+	return nil
+}
+
+// Run performs the api-resources operation.
+func (o *Options) Run() error {
+	resourceRegistry, err := o.discover()
+	if err != nil {
+		return err
+	}
+
+	resources := o.filter(resourceRegistry.Resources())
+	o.sort(resources)
+
+	if o.Output == nameOutput {
+		for _, resource := range resources {
+			fmt.Fprintln(o.Out, qualifiedName(resource))
+		}
+		return nil
+	}
+
+	o.print(o.Out, resources)
+
+	return nil
+}
+
+// discover returns the registry of the resources discovered from Non-K8s API or the cache. The built-in
+// registry is returned if discovery fails.
+func (o *Options) discover() (*registry.Registry, error) {
+	resourceRegistry, err := Discover(o.clientFactory, o.resourceRegistry, o.discoveryPath, o.Cached, o.ErrOut)
+	if err != nil {
+		if !errors.Is(err, client.ErrDiscoveryNotSupported) {
+			fmt.Fprintf(o.ErrOut, "warning: unable to discover the API resources, printing the built-in resources: %v\n",
+				err)
+		}
+		return o.resourceRegistry, nil
+	}
+
+	return resourceRegistry, nil
+}
+
+// Discover returns the registry of the resources discovered from the discovery path of Non-K8s API, or cached
+// for the current context and the URL of Non-K8s API, with the columns of the resources of the built-in registry.
+// Expired cached resources are used only if allowExpired is true. The resources of a fixed config of the client
+// factory, e.g. of a fake Non-K8s API, are not cached. A failure to cache the resources is reported as a warning to errOut.
+func Discover(clientFactory *client.Factory, builtin *registry.Registry, discoveryPath string,
+	allowExpired bool, errOut io.Writer) (*registry.Registry, error) {
+	configFlags := clientFactory.ConfigFlags()
+
+	contextName, err := pluginconfig.CurrentContext(configFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient, err := clientFactory.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var cache *registry.DiscoveryCache
+	if !clientFactory.IsFixed() {
+		cacheDir := ""
+		if configFlags.CacheDir != nil {
+			cacheDir = *configFlags.CacheDir
+		}
+
+		cache = registry.NewDiscoveryCache(filepath.Join(cacheDir, discoveryCacheDir), registry.DefaultCacheTTL)
+
+		if apiResourceLists, found := cache.Get(contextName, apiClient.URL(), allowExpired); found {
+			return registry.FromAPIResources(apiResourceLists, builtin)
+		}
+	}
+
+	apiResourceLists, err := apiClient.GetAPIResources(context.TODO(), discoveryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		if err := cache.Set(contextName, apiClient.URL(), apiResourceLists); err != nil {
+			fmt.Fprintf(errOut, "warning: unable to cache the API resources: %v\n", err)
+		}
+	}
+
+	return registry.FromAPIResources(apiResourceLists, builtin)
+}
+
+func (o *Options) filter(resources []*registry.Resource) []*registry.Resource {
+	filtered := make([]*registry.Resource, 0, len(resources))
+
+	for _, resource := range resources {
+		if o.APIGroup != "" && group(resource) != o.APIGroup {
+			continue
+		}
+		if o.namespacedSet && resource.Namespaced() != o.Namespaced {
+			continue
+		}
+		if len(o.Verbs) > 0 && !sets.NewString(resource.Verbs...).HasAll(o.Verbs...) {
+			continue
+		}
+		filtered = append(filtered, resource)
+	}
+
+	return filtered
+}
+
+// sort sorts the resources by --sort-by, or else by group and name like kubectl
+func (o *Options) sort(resources []*registry.Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		switch o.SortBy {
+		case sortByName:
+			return resources[i].Path < resources[j].Path
+		case sortByKind:
+			return kind(resources[i]) < kind(resources[j])
+		default:
+			if group(resources[i]) != group(resources[j]) {
+				return group(resources[i]) < group(resources[j])
+			}
+			return resources[i].Path < resources[j].Path
+		}
+	})
+}
+
+func (o *Options) print(out io.Writer, resources []*registry.Resource) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		headers := []string{"NAME", "SHORTNAMES", "APIVERSION", "NAMESPACED", "KIND"}
+		if o.Output == wideOutput {
+			headers = append(headers, "VERBS")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	for _, resource := range resources {
+		apiVersion := ""
+		if resource.Mapping != nil {
+			apiVersion = resource.Mapping.GroupVersionKind.GroupVersion().String()
+		}
+
+		cells := []string{
+			resource.Path, strings.Join(resource.ShortNames, ","), apiVersion,
+			fmt.Sprint(resource.Namespaced()), kind(resource),
+		}
+		if o.Output == wideOutput {
+			cells = append(cells, fmt.Sprint(resource.Verbs))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+}
+
+func group(resource *registry.Resource) string {
+	if resource.Mapping == nil {
+		return ""
+	}
+
+	return resource.Mapping.GroupVersionKind.Group
+}
+
+func kind(resource *registry.Resource) string {
+	if resource.Mapping == nil {
+		return ""
+	}
+
+	return resource.Mapping.GroupVersionKind.Kind
+}
+
+// qualifiedName returns the name of the resource qualified by its group, as printed by kubectl -o name
+func qualifiedName(resource *registry.Resource) string {
+	if group(resource) == "" {
+		return resource.Path
+	}
+
+	return fmt.Sprintf("%s.%s", resource.Path, group(resource))
+}
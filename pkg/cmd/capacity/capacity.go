@@ -0,0 +1,290 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+var (
+	capacityLong = templates.LongDesc(i18n.T(`
+		Display the capacity and the allocatable resources of the managed clusters.
+
+		Prints a row for every managed cluster and a total row, with CPU in cores and
+		memory in GiB. The managed clusters can be filtered by a label selector and by
+		their leaf hub, and sorted by the capacity or the allocatable amount of any resource.`))
+
+	capacityExample = templates.Examples(i18n.T(`
+		# Show the capacity of all managed clusters
+		%[1]s capacity
+
+		# Show the capacity of the managed clusters of the leaf hub 'hub1', sorted by memory
+		%[1]s capacity --hub hub1 --sort-by memory
+
+		# Show the capacity of the production managed clusters, sorted by allocatable CPU
+		%[1]s top -l environment=prod --sort-by allocatable.cpu
+
+		# Show the capacity of pods and GPUs as well
+		%[1]s capacity --resources cpu,memory,pods,nvidia.com/gpu`))
+
+	errInvalidSortBy = errors.New("invalid --sort-by")
+)
+
+const (
+	allocatablePrefix = "allocatable."
+	totalRowName      = "TOTAL"
+	milliPerCore      = 1000
+	bytesPerGiB       = 1 << 30
+)
+
+// Options contains the input to the capacity command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	LeafHub       string
+	SortBy        string
+	Resources     []string
+	NoHeaders     bool
+
+	client   *client.Client
+	selector labels.Selector
+}
+
+// NewOptions returns an Options for the capacity command, showing CPU and memory by default.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Resources: []string{string(clusterv1.ResourceCPU), string(clusterv1.ResourceMemory)},
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "capacity" action, which shows the capacity of the managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "capacity [-l label] [--hub HUB] [--sort-by RESOURCE]",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"top"},
+		Short:                 i18n.T("Display the capacity of the managed clusters"),
+		Long:                  capacityLong,
+		Example:               fmt.Sprintf(capacityExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().StringVar(&o.LeafHub, "hub", o.LeafHub, "If present, only show the managed clusters of this leaf hub.")
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "If non-empty, sort the managed clusters by the capacity of this resource in descending order (e.g. 'memory'). Prefix the resource with 'allocatable.' to sort by its allocatable amount.")
+	cmd.Flags().StringSliceVar(&o.Resources, "resources", o.Resources, "The resources to show.")
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When true, don't print headers.")
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector", completion.ManagedClusterLabelSelectors(clientFactory)))
+	cmdutil.CheckErr(pluginconfig.MarkFlagSetting(cmd.Flags(), "selector", pluginconfig.SelectorKey))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("hub", completion.HubNames(clientFactory)))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("sort-by",
+		completion.ManagedClusterValues(clientFactory, sortByValues)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimPrefix(o.SortBy, allocatablePrefix) == "" && o.SortBy != "" {
+		return fmt.Errorf("%w: %q", errInvalidSortBy, o.SortBy)
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run performs the capacity operation.
+func (o *Options) Run() error {
+	managedClusters, err := o.client.ListManagedClusters(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	managedClusters = o.filter(managedClusters)
+	if len(managedClusters) == 0 {
+		fmt.Fprintln(o.ErrOut, "No resources found")
+		return nil
+	}
+
+	o.sort(managedClusters)
+	o.print(o.Out, managedClusters)
+
+	return nil
+}
+
+func (o *Options) filter(managedClusters []*clusterv1.ManagedCluster) []*clusterv1.ManagedCluster {
+	filtered := make([]*clusterv1.ManagedCluster, 0, len(managedClusters))
+
+	for _, managedCluster := range managedClusters {
+		if !o.selector.Matches(labels.Set(managedCluster.GetLabels())) {
+			continue
+		}
+		if o.LeafHub != "" && pluginutil.GetLeafHubName(managedCluster) != o.LeafHub {
+			continue
+		}
+		filtered = append(filtered, managedCluster)
+	}
+
+	return filtered
+}
+
+// sortByValues returns the values of --sort-by for the managed cluster: the resources of its capacity, and the
+// resources of its allocatable amounts prefixed by allocatable.
+func sortByValues(obj *unstructured.Unstructured) []string {
+	capacity, _, _ := unstructured.NestedMap(obj.Object, "status", "capacity")
+	allocatable, _, _ := unstructured.NestedMap(obj.Object, "status", "allocatable")
+
+	values := make([]string, 0, len(capacity)+len(allocatable))
+	for resource := range capacity {
+		values = append(values, resource)
+	}
+
+	for resource := range allocatable {
+		values = append(values, allocatablePrefix+resource)
+	}
+
+	return values
+}
+
+// sort sorts the managed clusters by the resource of --sort-by in descending order, or else by name
+func (o *Options) sort(managedClusters []*clusterv1.ManagedCluster) {
+	if o.SortBy == "" {
+		sort.SliceStable(managedClusters, func(i, j int) bool {
+			return managedClusters[i].Name < managedClusters[j].Name
+		})
+		return
+	}
+
+	resourceName := clusterv1.ResourceName(strings.TrimPrefix(o.SortBy, allocatablePrefix))
+	resourceList := func(managedCluster *clusterv1.ManagedCluster) clusterv1.ResourceList {
+		if strings.HasPrefix(o.SortBy, allocatablePrefix) {
+			return managedCluster.Status.Allocatable
+		}
+		return managedCluster.Status.Capacity
+	}
+
+	sort.SliceStable(managedClusters, func(i, j int) bool {
+		quantityI := resourceList(managedClusters[i])[resourceName]
+		quantityJ := resourceList(managedClusters[j])[resourceName]
+		return quantityI.Cmp(quantityJ) > 0
+	})
+}
+
+func (o *Options) print(out io.Writer, managedClusters []*clusterv1.ManagedCluster) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		headers := []string{"NAME", "HUB"}
+		for _, resourceName := range o.Resources {
+			upper := strings.ToUpper(resourceName)
+			headers = append(headers, upper+" CAPACITY", upper+" ALLOCATABLE")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	totalCapacity := clusterv1.ResourceList{}
+	totalAllocatable := clusterv1.ResourceList{}
+
+	for _, managedCluster := range managedClusters {
+		leafHub := pluginutil.GetLeafHubName(managedCluster)
+		if leafHub == "" {
+			leafHub = pluginutil.NoneValue
+		}
+
+		cells := []string{managedCluster.Name, leafHub}
+		for _, resourceName := range o.Resources {
+			name := clusterv1.ResourceName(resourceName)
+			cells = append(cells,
+				formatQuantity(name, managedCluster.Status.Capacity),
+				formatQuantity(name, managedCluster.Status.Allocatable))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+
+		addResources(totalCapacity, managedCluster.Status.Capacity)
+		addResources(totalAllocatable, managedCluster.Status.Allocatable)
+	}
+
+	cells := []string{totalRowName, fmt.Sprintf("%d clusters", len(managedClusters))}
+	for _, resourceName := range o.Resources {
+		name := clusterv1.ResourceName(resourceName)
+		cells = append(cells, formatQuantity(name, totalCapacity), formatQuantity(name, totalAllocatable))
+	}
+	fmt.Fprintln(w, strings.Join(cells, "\t"))
+}
+
+func addResources(total, resources clusterv1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// formatQuantity normalizes the units of the quantity of the resource: CPU in cores, memory and storage in GiB
+func formatQuantity(name clusterv1.ResourceName, resources clusterv1.ResourceList) string {
+	quantity, found := resources[name]
+	if !found {
+		return pluginutil.NoneValue
+	}
+
+	switch corev1.ResourceName(name) {
+	case corev1.ResourceCPU:
+		return strconv.FormatFloat(float64(quantity.MilliValue())/milliPerCore, 'f', -1, 64)
+	case corev1.ResourceMemory, corev1.ResourceStorage, corev1.ResourceEphemeralStorage:
+		return strconv.FormatFloat(float64(quantity.Value())/bytesPerGiB, 'f', 1, 64) + "Gi"
+	default:
+		return quantity.String()
+	}
+}
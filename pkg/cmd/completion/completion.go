@@ -0,0 +1,155 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package completion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const (
+	// kubectlPluginPrefix is the prefix of the executables of kubectl plugins
+	kubectlPluginPrefix = "kubectl-"
+	// kubectlCompletionScript completes the plugin when invoked by kubectl, from kubectl 1.26, as the
+	// kubectl_complete-<plugin> executable in PATH, by calling the __complete command of cobra
+	kubectlCompletionScript = `#!/usr/bin/env sh
+
+# Completion of 'kubectl %[2]s', install as an executable named kubectl_complete-%[3]s in PATH
+%[1]s __complete "$@"
+`
+)
+
+var (
+	completionLong = templates.LongDesc(i18n.T(`
+		Output shell completion code for the specified shell (bash, zsh or fish), or the completion script
+		of kubectl plugins (kubectl).
+
+		The shell code completes the commands, the flags, the names of managed clusters and leaf hubs, the
+		label selectors and the output formats. The names and labels are fetched from Non-K8s API and cached
+		briefly in the cache directory.
+
+		The shell code is generated for the name of the executable, e.g. kubectl-mcl. To complete the plugin
+		when invoked as a kubectl subcommand, e.g. 'kubectl mcl', kubectl 1.26 or later calls the executable
+		kubectl_complete-mcl in PATH, output by the kubectl argument.`))
+
+	completionExample = templates.Examples(i18n.T(`
+		# Load the completion code for bash into the current shell
+		source <(%[1]s completion bash)
+
+		# Load the completion code for zsh into the current shell
+		source <(%[1]s completion zsh)
+
+		# Load the completion code for fish into the current shell
+		%[1]s completion fish | source
+
+		# Complete 'kubectl mcl' through the kubectl plugin completion
+		%[1]s completion kubectl > /usr/local/bin/kubectl_complete-mcl
+		chmod +x /usr/local/bin/kubectl_complete-mcl`))
+)
+
+var completionShells = map[string]func(out io.Writer, root *cobra.Command) error{
+	"bash":    runCompletionBash,
+	"zsh":     runCompletionZsh,
+	"fish":    runCompletionFish,
+	"kubectl": runCompletionKubectl,
+}
+
+// Options holds the options of the completion command
+type Options struct {
+	genericclioptions.IOStreams
+
+	shell string
+}
+
+// NewOptions returns initialized Options.
+func NewOptions(streams genericclioptions.IOStreams) *Options {
+	return &Options{IOStreams: streams}
+}
+
+// NewCmd creates a command object for the "completion" action, which outputs the shell completion code.
+func NewCmd(parent string, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	shells := make([]string, 0, len(completionShells))
+	for shell := range completionShells {
+		shells = append(shells, shell)
+	}
+
+	sort.Strings(shells)
+
+	cmd := &cobra.Command{
+		Use:                   fmt.Sprintf("completion %s", strings.Join(shells, "|")),
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Output shell completion code for the specified shell (bash, zsh or fish)"),
+		Long:                  completionLong,
+		Example:               fmt.Sprintf(completionExample, parent),
+		ValidArgs:             shells,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run(cmd))
+		},
+	}
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one shell must be specified, got: %v", args)
+	}
+
+	if _, found := completionShells[args[0]]; !found {
+		return cmdutil.UsageErrorf(cmd, "unsupported shell type %q", args[0])
+	}
+
+	o.shell = args[0]
+
+	return nil
+}
+
+// Run outputs the completion code of the root command, named after the executable, so that the shell
+// completes the executable as invoked.
+func (o *Options) Run(cmd *cobra.Command) error {
+	root := cmd.Root()
+	root.Use = executableName()
+
+	return completionShells[o.shell](o.Out, root)
+}
+
+func runCompletionBash(out io.Writer, root *cobra.Command) error {
+	return root.GenBashCompletionV2(out, true)
+}
+
+func runCompletionZsh(out io.Writer, root *cobra.Command) error {
+	return root.GenZshCompletion(out)
+}
+
+func runCompletionFish(out io.Writer, root *cobra.Command) error {
+	return root.GenFishCompletion(out, true)
+}
+
+func runCompletionKubectl(out io.Writer, root *cobra.Command) error {
+	plugin := strings.TrimPrefix(root.Name(), kubectlPluginPrefix)
+
+	// kubectl replaces the dashes of the names of the plugins by underscores in the completion executables
+	_, err := fmt.Fprintf(out, kubectlCompletionScript, root.Name(), plugin, strings.ReplaceAll(plugin, "-", "_"))
+
+	return err
+}
+
+// executableName returns the name the command is invoked as, e.g. kubectl-mcl
+func executableName() string {
+	return filepath.Base(os.Args[0])
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "types",
+			args:     []string{"describe", "p"},
+			expected: "placements,placementdecisions,policies,placementbindings,placementrules",
+		},
+		{
+			name:     "names not specified yet",
+			args:     []string{"describe", "mcl", "cluster1", ""},
+			expected: "cluster2,cluster3,cluster4",
+		},
+		{name: "unknown type", args: []string{"describe", "cluster1", ""}, expected: ""},
+		{name: "names of a type", args: []string{"describe", "policies", ""}, expected: "policy-pod"},
+		{
+			name:     "types only",
+			args:     []string{"get", "pl"},
+			expected: "placements,placementdecisions,placementbindings,placementrules",
+		},
+		{name: "single cluster", args: []string{"kubeconfig", "cluster1", ""}, expected: ""},
+		{name: "hubs", args: []string{"exec", "--hub", ""}, expected: "hub1,hub2"},
+		{name: "label keys", args: []string{"get", "-l", "e"}, expected: "environment="},
+		{
+			name:     "label values",
+			args:     []string{"get", "-l", "cloud=Amazon,environment="},
+			expected: "cloud=Amazon,environment=dev,cloud=Amazon,environment=prod",
+		},
+		{name: "label values not equal", args: []string{"exec", "-l", "environment!=p"}, expected: "environment!=prod"},
+		{name: "sort-by", args: []string{"get", "policies", "--sort-by", ".status."}, expected: ".status.compliant"},
+		{
+			name:     "sort-by braces",
+			args:     []string{"get", "--sort-by", "{.metadata.labels.e"},
+			expected: "{.metadata.labels.environment}",
+		},
+		{
+			name:     "capacity sort-by",
+			args:     []string{"capacity", "--sort-by", "allocatable."},
+			expected: "allocatable.cpu,allocatable.memory",
+		},
+		{name: "output", args: []string{"summary", "-o", ""}, expected: "json,yaml"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			newServer(t)
+
+			args := append([]string{"__complete", "--cache-dir", t.TempDir()}, test.args...)
+
+			// the error output reports the directive for debugging
+			out, _ := run(t, args...)
+
+			// the completions are followed by a line with the directive
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if got := strings.Join(lines[:len(lines)-1], ","); got != test.expected {
+				t.Errorf("unexpected completions: %q, expected %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out, errOut := run(t, "completion", shell)
+		if errOut != "" {
+			t.Fatalf("unexpected error output: %s", errOut)
+		}
+
+		expectContains(t, out, "__complete")
+	}
+
+	out, _ := run(t, "completion", "kubectl")
+	expectContains(t, out, `__complete "$@"`)
+
+	_, errOut := run(t, "completion", "powershell")
+	expectContains(t, errOut, "unsupported shell type")
+}
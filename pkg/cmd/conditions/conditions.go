@@ -0,0 +1,229 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package conditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+var (
+	conditionsLong = templates.LongDesc(i18n.T(`
+		Display the status conditions of managed clusters, one row per condition.
+
+		The conditions can be filtered by their type, by their status and by the time
+		since their last transition, to find the managed clusters that are unhealthy.
+		If no NAME is specified, the conditions of all the managed clusters are displayed.`))
+
+	conditionsExample = templates.Examples(i18n.T(`
+		# List the conditions of all managed clusters
+		%[1]s conditions
+
+		# List the conditions of the managed cluster 'mycluster'
+		%[1]s conditions mycluster
+
+		# List the managed clusters that have been unavailable for more than 30 minutes
+		%[1]s conditions --type ManagedClusterConditionAvailable --status False,Unknown --older-than 30m`))
+
+	errInvalidStatus = errors.New("invalid --status")
+)
+
+// Options contains the input to the conditions command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	Types         []string
+	Statuses      []string
+	OlderThan     time.Duration
+	NoHeaders     bool
+
+	client   *client.Client
+	names    sets.String
+	selector labels.Selector
+}
+
+// conditionRow is a condition of a managed cluster
+type conditionRow struct {
+	cluster   string
+	condition metav1.Condition
+}
+
+// NewOptions returns an Options for the conditions command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "conditions" action, which lists the conditions of managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "conditions [NAME...] [-l label] [--type TYPE] [--status STATUS] [--older-than DURATION]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Display the status conditions of managed clusters"),
+		Long:                  conditionsLong,
+		Example:               fmt.Sprintf(conditionsExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().StringSliceVar(&o.Types, "type", o.Types, "If present, only show the conditions of these types.")
+	cmd.Flags().StringSliceVar(&o.Statuses, "status", o.Statuses, "If present, only show the conditions with these statuses. One or more of: True|False|Unknown.")
+	cmd.Flags().DurationVar(&o.OlderThan, "older-than", o.OlderThan, "If non-zero, only show the conditions whose last transition is older than this duration (e.g. 30m).")
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When true, don't print headers.")
+
+	cmd.ValidArgsFunction = completion.ManagedClusterNames(clientFactory, true)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector", completion.ManagedClusterLabelSelectors(clientFactory)))
+	cmdutil.CheckErr(pluginconfig.MarkFlagSetting(cmd.Flags(), "selector", pluginconfig.SelectorKey))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.names = sets.NewString(args...)
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	for _, status := range o.Statuses {
+		switch metav1.ConditionStatus(status) {
+		case metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown:
+		default:
+			return fmt.Errorf("%w: %q, must be one of True|False|Unknown", errInvalidStatus, status)
+		}
+	}
+
+	return nil
+}
+
+// Run performs the conditions operation.
+func (o *Options) Run() error {
+	managedClusters, err := o.client.ListManagedClusters(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	rows := o.conditionRows(managedClusters)
+	if len(rows) == 0 {
+		fmt.Fprintln(o.ErrOut, "No resources found")
+		return nil
+	}
+
+	o.print(o.Out, rows)
+
+	return nil
+}
+
+// conditionRows returns the conditions of the selected managed clusters that match the filters,
+// ordered by cluster and type
+func (o *Options) conditionRows(managedClusters []*clusterv1.ManagedCluster) []conditionRow {
+	types := sets.NewString(o.Types...)
+	statuses := sets.NewString(o.Statuses...)
+
+	var rows []conditionRow
+
+	for _, managedCluster := range managedClusters {
+		if o.names.Len() > 0 && !o.names.Has(managedCluster.Name) {
+			continue
+		}
+		if !o.selector.Matches(labels.Set(managedCluster.GetLabels())) {
+			continue
+		}
+
+		for _, condition := range managedCluster.Status.Conditions {
+			if types.Len() > 0 && !types.Has(condition.Type) {
+				continue
+			}
+			if statuses.Len() > 0 && !statuses.Has(string(condition.Status)) {
+				continue
+			}
+			if o.OlderThan > 0 && time.Since(condition.LastTransitionTime.Time) < o.OlderThan {
+				continue
+			}
+			rows = append(rows, conditionRow{cluster: managedCluster.Name, condition: condition})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].cluster != rows[j].cluster {
+			return rows[i].cluster < rows[j].cluster
+		}
+		return rows[i].condition.Type < rows[j].condition.Type
+	})
+
+	return rows
+}
+
+func (o *Options) print(out io.Writer, rows []conditionRow) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		fmt.Fprintln(w, "CLUSTER\tTYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+	}
+
+	for _, row := range rows {
+		lastTransition := pluginutil.NoneValue
+		if !row.condition.LastTransitionTime.IsZero() {
+			lastTransition = duration.HumanDuration(time.Since(row.condition.LastTransitionTime.Time)) + " ago"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", row.cluster, row.condition.Type, row.condition.Status,
+			pluginutil.ValueOrNone(row.condition.Reason), lastTransition,
+			pluginutil.ValueOrNone(oneLine(row.condition.Message)))
+	}
+}
+
+// oneLine replaces the line breaks of multiline messages, so that every condition is printed in one row
+func oneLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
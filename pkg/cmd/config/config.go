@@ -0,0 +1,75 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	configLong = templates.LongDesc(i18n.T(`
+		Modify the config file of the plugin using subcommands like "config set output wide".
+
+		The config file holds settings per kubeconfig context, applied to the commands run against the
+		context: the current context, or the context of the --context flag. The settings are:
+
+		    url: the URL of Non-K8s API, instead of the URL derived from the API server of the hub of hubs
+		    caFile: the file of the certificate authorities verifying the certificate of Non-K8s API,
+		      instead of the certificate authority of the hub of hubs in the kubeconfig
+		    insecureSkipTLSVerify: if true, the certificate of Non-K8s API is not verified
+		    output: the default output format of the get command
+		    selector: the default label selector of the get, capacity and conditions commands
+		    columns: the default labels of the get command presented as columns, comma separated
+		    timeout: the time to wait for the responses of Non-K8s API, e.g. 30s
+		    retries: the number of times the reading requests are retried on transient failures
+		    retryBackoff: the delay before the first retry, doubled for each next retry, e.g. 1s
+
+		The settings of the flags are defaults, the flags specified on the command line take precedence,
+		e.g. -l '' lists all the managed clusters despite a selector setting.
+
+		The config file is ~/.kube/mcl/config.yaml, or the file of the MCL_CONFIG environment variable.`))
+
+	configExample = templates.Examples(i18n.T(`
+		# Display the config file
+		%[1]s config view
+
+		# Display the settings of the current context
+		%[1]s config view --minify
+
+		# Print the managed clusters in the wide output format by default
+		%[1]s config set output wide
+
+		# Retry the reading requests to the hub of hubs of the context 'hoh' three times
+		%[1]s config set retries 3 --context hoh
+
+		# Stop printing the managed clusters in the wide output format by default
+		%[1]s config unset output`))
+)
+
+// NewCmd creates a command object for the "config" action, which manages the config file of the plugin.
+func NewCmd(parent string, configFlags *genericclioptions.ConfigFlags,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "config SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Modify the config file of the plugin"),
+		Long:                  configLong,
+		Example:               fmt.Sprintf(configExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			//nolint:errcheck
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewCmdView(configFlags, streams))
+	cmd.AddCommand(NewCmdSet(configFlags, streams))
+	cmd.AddCommand(NewCmdUnset(configFlags, streams))
+
+	return cmd
+}
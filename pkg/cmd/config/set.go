@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+// SetOptions holds the options of the config set and unset commands
+type SetOptions struct {
+	genericclioptions.IOStreams
+	configFlags *genericclioptions.ConfigFlags
+
+	path  string
+	key   string
+	value string
+	unset bool
+}
+
+// NewSetOptions returns initialized SetOptions.
+func NewSetOptions(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams,
+	unset bool) *SetOptions {
+	return &SetOptions{
+		configFlags: configFlags,
+		IOStreams:   streams,
+		unset:       unset,
+	}
+}
+
+// NewCmdSet creates a command object for the "config set" action, which sets a setting of the current context.
+func NewCmdSet(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewSetOptions(configFlags, streams, false)
+
+	cmd := &cobra.Command{
+		Use:                   fmt.Sprintf("set (%s) VALUE", strings.Join(pluginconfig.Keys(), "|")),
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Set a setting of the current context in the config file"),
+		ValidArgsFunction:     keys,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	return cmd
+}
+
+// NewCmdUnset creates a command object for the "config unset" action, which unsets a setting of the current
+// context.
+func NewCmdUnset(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewSetOptions(configFlags, streams, true)
+
+	cmd := &cobra.Command{
+		Use:                   fmt.Sprintf("unset (%s)", strings.Join(pluginconfig.Keys(), "|")),
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Unset a setting of the current context in the config file"),
+		ValidArgsFunction:     keys,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *SetOptions) Complete(cmd *cobra.Command, args []string) error {
+	switch {
+	case o.unset && len(args) != 1:
+		return cmdutil.UsageErrorf(cmd, "exactly one setting must be specified, got: %v", args)
+	case !o.unset && len(args) != 2:
+		return cmdutil.UsageErrorf(cmd, "exactly one setting and its value must be specified, got: %v", args)
+	}
+
+	o.key = args[0]
+	if !o.unset {
+		o.value = args[1]
+	}
+
+	o.path = pluginconfig.Path()
+
+	return nil
+}
+
+// Run sets or unsets the setting of the current context and saves the config file.
+func (o *SetOptions) Run() error {
+	config, err := pluginconfig.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	contextName, err := pluginconfig.CurrentContext(o.configFlags)
+	if err != nil {
+		return err
+	}
+
+	settings := config.SettingsFor(contextName)
+	if err := settings.Set(o.key, o.value); err != nil {
+		return err
+	}
+
+	config.SetSettings(contextName, settings)
+
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+
+	action := "set"
+	if o.unset || o.value == "" {
+		action = "unset"
+	}
+
+	fmt.Fprintf(o.Out, "Property %q %s for context %q.\n", o.key, action, contextName)
+
+	return nil
+}
+
+// keys completes the first argument with the keys of the settings
+func keys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completion.Values(pluginconfig.Keys()...)(cmd, args, toComplete)
+}
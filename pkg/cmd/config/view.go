@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"sigs.k8s.io/yaml"
+)
+
+// ViewOptions holds the options of the config view command
+type ViewOptions struct {
+	genericclioptions.IOStreams
+	configFlags *genericclioptions.ConfigFlags
+
+	Minify bool
+
+	path string
+}
+
+// NewViewOptions returns initialized ViewOptions.
+func NewViewOptions(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *ViewOptions {
+	return &ViewOptions{
+		configFlags: configFlags,
+		IOStreams:   streams,
+	}
+}
+
+// NewCmdView creates a command object for the "config view" action, which displays the config file.
+func NewCmdView(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewViewOptions(configFlags, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "view [--minify]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Display the config file of the plugin"),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Minify, "minify", o.Minify, "Remove all settings not used by the current context from the output.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *ViewOptions) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	o.path = pluginconfig.Path()
+
+	return nil
+}
+
+// Run displays the config file, or the settings of the current context if minified.
+func (o *ViewOptions) Run() error {
+	config, err := pluginconfig.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	if o.Minify {
+		contextName, err := pluginconfig.CurrentContext(o.configFlags)
+		if err != nil {
+			return err
+		}
+
+		minified := &pluginconfig.Config{Views: config.Views}
+		minified.SetSettings(contextName, config.SettingsFor(contextName))
+		config = minified
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("unable to encode the config: %w", err)
+	}
+
+	_, err = o.Out.Write(data)
+
+	return err
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package describe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/snapshot"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	describeLong = templates.LongDesc(i18n.T(`
+		Show details of a specific resource or group of resources.
+
+		Print a detailed description of the selected resources, including the resources
+		reported by the leaf hubs and their related events. A NAME requires a TYPE. If no TYPE
+		is specified, managed clusters are described. If no NAME is specified, all the resources
+		of the type that match the label selector are described.`))
+
+	describeExample = templates.Examples(i18n.T(`
+		# Describe the managed cluster 'mycluster'
+		%[1]s describe managedcluster mycluster
+
+		# Describe the production managed clusters
+		%[1]s describe managedclusters -l environment=prod
+
+		# Describe the policy 'policy-pod' in the namespace 'default'
+		%[1]s describe policy policy-pod -n default
+
+		# Describe the managed cluster 'mycluster' as exported to the snapshot directory 'fleet-snapshot'
+		%[1]s describe managedcluster mycluster --from-snapshot fleet-snapshot`))
+
+	errNotFound = errors.New("not found")
+)
+
+// Options contains the input to the describe command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	AllNamespaces bool
+	ShowEvents    bool
+	FromSnapshot  string
+
+	client     *client.Client
+	registry   *registry.Registry
+	eventsPath string
+	resource   *registry.Resource
+	names      []string
+	namespace  string
+	selector   labels.Selector
+}
+
+// NewOptions returns an Options for the describe command, showing events by default.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry, eventsPath string) *Options {
+	return &Options{
+		ShowEvents: true,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		registry:      resourceRegistry,
+		eventsPath:    eventsPath,
+	}
+}
+
+// NewCmd creates a command object for the "describe" action, which describes resources of Non-K8s API.
+// The events path is the path of events in Non-K8s API.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry, eventsPath string) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourceRegistry, eventsPath)
+
+	cmd := &cobra.Command{
+		Use:                   "describe ([TYPE] -l label | TYPE NAME...)",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Show details of a specific resource or group of resources"),
+		Long:                  describeLong,
+		Example:               fmt.Sprintf(describeExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, describe the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().BoolVar(&o.ShowEvents, "show-events", o.ShowEvents, "If true, display events related to the described object.")
+	cmd.Flags().StringVar(&o.FromSnapshot, "from-snapshot", o.FromSnapshot, "If present, read the resources from the snapshot in this directory, created by the export command, instead of the hub.")
+
+	cmd.ValidArgsFunction = completion.ResourceTypesAndNames(clientFactory, resourceRegistry, registry.VerbGet, true)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector",
+		completion.LabelSelectors(clientFactory, resourceRegistry, registry.VerbGet)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.resource, o.names, err = o.registry.ResourceFromArgs(args, registry.VerbGet)
+	if err != nil {
+		return err
+	}
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if o.FromSnapshot != "" {
+		return nil
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run performs the describe operation.
+func (o *Options) Run() error {
+	objs, err := o.getObjects(o.resource.Path)
+	if err != nil {
+		return err
+	}
+
+	selected, err := o.selectObjects(objs)
+	if err != nil {
+		return err
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintln(o.ErrOut, "No resources found")
+		return nil
+	}
+
+	events := o.getEvents()
+
+	w := printers.GetNewTabWriter(o.Out)
+	defer w.Flush()
+
+	for i, obj := range selected {
+		if i > 0 {
+			fmt.Fprintf(w, "\n\n")
+		}
+		describeObject(NewPrefixWriter(w), obj, relatedEvents(events, obj))
+	}
+
+	return nil
+}
+
+// getObjects returns the objects of the resource from the snapshot if --from-snapshot is specified,
+// or else from Non-K8s API
+func (o *Options) getObjects(resourcePath string) ([]*unstructured.Unstructured, error) {
+	if o.FromSnapshot == "" {
+		return o.client.List(context.TODO(), resourcePath)
+	}
+
+	objs, err := snapshot.GetResourceObjects(o.FromSnapshot, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	unstructuredObjs := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		if unstructuredObj, ok := obj.(*unstructured.Unstructured); ok {
+			unstructuredObjs = append(unstructuredObjs, unstructuredObj)
+		}
+	}
+
+	return unstructuredObjs, nil
+}
+
+// selectObjects returns the objects with the requested names that match the selector and the namespace,
+// ordered by namespace and name. It fails if an object with a requested name is not found.
+func (o *Options) selectObjects(objs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	byName := map[string]*unstructured.Unstructured{}
+	var selected []*unstructured.Unstructured
+
+	for _, obj := range objs {
+		if o.resource.Namespaced() && !o.AllNamespaces && obj.GetNamespace() != o.namespace {
+			continue
+		}
+		if !o.selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		if len(o.names) == 0 {
+			selected = append(selected, obj)
+			continue
+		}
+		byName[obj.GetName()] = obj
+	}
+
+	var notFound []string
+
+	for _, name := range o.names {
+		if obj, found := byName[name]; found {
+			selected = append(selected, obj)
+		} else {
+			notFound = append(notFound, name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("%w: %s %q", errNotFound, o.resource.Name(), strings.Join(notFound, ", "))
+	}
+
+	if len(o.names) == 0 {
+		sort.SliceStable(selected, func(i, j int) bool {
+			if selected[i].GetNamespace() != selected[j].GetNamespace() {
+				return selected[i].GetNamespace() < selected[j].GetNamespace()
+			}
+			return selected[i].GetName() < selected[j].GetName()
+		})
+	}
+
+	return selected, nil
+}
+
+// getEvents returns the events if --show-events is true. The events are optional, so nil is returned
+// if they cannot be read, and then no events section is printed.
+func (o *Options) getEvents() []*corev1.Event {
+	if !o.ShowEvents {
+		return nil
+	}
+
+	objs, err := o.getObjects(o.eventsPath)
+	if err != nil {
+		return nil
+	}
+
+	return toEvents(objs)
+}
+
+// DescribeObject writes the description of the object as printed by the describe command, with its events
+// among the event objects. No events are described if the event objects are nil.
+func DescribeObject(out io.Writer, obj *unstructured.Unstructured, eventObjs []*unstructured.Unstructured) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	describeObject(NewPrefixWriter(w), obj, relatedEvents(toEvents(eventObjs), obj))
+}
+
+// toEvents converts the event objects to events, skipping the objects that are not events, nil if the objects
+// are nil
+func toEvents(objs []*unstructured.Unstructured) []*corev1.Event {
+	if objs == nil {
+		return nil
+	}
+
+	events := make([]*corev1.Event, 0, len(objs))
+
+	for _, obj := range objs {
+		event := &corev1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// relatedEvents returns the events whose involved object is the object, nil if the events are nil
+func relatedEvents(events []*corev1.Event, obj *unstructured.Unstructured) []*corev1.Event {
+	if events == nil {
+		return nil
+	}
+
+	related := []*corev1.Event{}
+
+	for _, event := range events {
+		if event.InvolvedObject.Kind == obj.GetKind() && event.InvolvedObject.Name == obj.GetName() &&
+			event.InvolvedObject.Namespace == obj.GetNamespace() {
+			related = append(related, event)
+		}
+	}
+
+	return related
+}
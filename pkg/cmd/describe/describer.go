@@ -0,0 +1,286 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// adopted from https://github.com/kubernetes/kubectl/blob/master/pkg/describe/describe.go
+
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// nolint
+package describe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	LEVEL_0 = iota
+	LEVEL_1
+	LEVEL_2
+	LEVEL_3
+	LEVEL_4
+)
+
+// maxAnnotationLen is the maximum length of an annotation printed in one line
+const maxAnnotationLen = 140
+
+// PrefixWriter can write text at various indentation levels.
+type PrefixWriter interface {
+	// Write writes text with the specified indentation level.
+	Write(level int, format string, a ...interface{})
+	// WriteLine writes an entire line with no indentation level.
+	WriteLine(a ...interface{})
+}
+
+// prefixWriter implements PrefixWriter
+type prefixWriter struct {
+	out io.Writer
+}
+
+var _ PrefixWriter = &prefixWriter{}
+
+// NewPrefixWriter creates a new PrefixWriter.
+func NewPrefixWriter(out io.Writer) PrefixWriter {
+	return &prefixWriter{out: out}
+}
+
+func (pw *prefixWriter) Write(level int, format string, a ...interface{}) {
+	levelSpace := "  "
+	prefix := ""
+	for i := 0; i < level; i++ {
+		prefix += levelSpace
+	}
+	fmt.Fprintf(pw.out, prefix+format, a...)
+}
+
+func (pw *prefixWriter) WriteLine(a ...interface{}) {
+	fmt.Fprintln(pw.out, a...)
+}
+
+// describeObject describes an unstructured object like the generic describer of kubectl,
+// followed by its events if they are not nil
+func describeObject(w PrefixWriter, obj *unstructured.Unstructured, events []*corev1.Event) {
+	w.Write(LEVEL_0, "Name:\t%s\n", obj.GetName())
+	w.Write(LEVEL_0, "Namespace:\t%s\n", obj.GetNamespace())
+	printLabelsMultiline(w, "Labels", obj.GetLabels())
+	printAnnotationsMultiline(w, "Annotations", obj.GetAnnotations())
+	printUnstructuredContent(w, LEVEL_0, obj.UnstructuredContent(), "", ".metadata.name", ".metadata.namespace",
+		".metadata.labels", ".metadata.annotations", ".metadata.managedFields")
+	if events != nil {
+		describeEvents(w, events)
+	}
+}
+
+func printUnstructuredContent(w PrefixWriter, level int, content map[string]interface{}, skipPrefix string, skip ...string) {
+	fields := []string{}
+	for field := range content {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	skipSet := sets.NewString(skip...)
+
+	for _, field := range fields {
+		value := content[field]
+		skipExpr := fmt.Sprintf("%s.%s", skipPrefix, field)
+		if skipSet.Has(skipExpr) {
+			continue
+		}
+
+		switch typedValue := value.(type) {
+		case map[string]interface{}:
+			w.Write(level, "%s:\n", smartLabelFor(field))
+			printUnstructuredContent(w, level+1, typedValue, skipExpr, skip...)
+
+		case []interface{}:
+			w.Write(level, "%s:\n", smartLabelFor(field))
+			for _, child := range typedValue {
+				switch typedChild := child.(type) {
+				case map[string]interface{}:
+					printUnstructuredContent(w, level+1, typedChild, skipExpr, skip...)
+				default:
+					w.Write(level+1, "%v\n", typedChild)
+				}
+			}
+
+		default:
+			w.Write(level, "%s:\t%v\n", smartLabelFor(field), typedValue)
+		}
+	}
+}
+
+// smartLabelFor converts a camel case field name into words, e.g. hubAcceptsClient into Hub Accepts Client
+func smartLabelFor(field string) string {
+	// skip creating smart label if field name contains
+	// special characters other than '-'
+	if strings.IndexFunc(field, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	}) != -1 {
+		return field
+	}
+
+	commonAcronyms := sets.NewString("API", "URL", "UID", "OSB", "GUID")
+	parts := splitCamelCase(field)
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if commonAcronyms.Has(strings.ToUpper(part)) {
+			part = strings.ToUpper(part)
+		} else {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		result = append(result, part)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// splitCamelCase splits a camel case string into its words, keeping runs of upper case letters together,
+// e.g. lastTransitionTime into last, Transition, Time and clusterAPIURL into cluster, APIURL
+func splitCamelCase(s string) []string {
+	runes := []rune(s)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '-':
+			continue
+		case unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]):
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		case unicode.IsLower(runes[i]) && unicode.IsUpper(runes[i-1]) && i-1 > start:
+			parts = append(parts, string(runes[start:i-1]))
+			start = i - 1
+		}
+	}
+
+	return append(parts, string(runes[start:]))
+}
+
+func printLabelsMultiline(w PrefixWriter, title string, labels map[string]string) {
+	w.Write(LEVEL_0, "%s:\t", title)
+
+	if len(labels) == 0 {
+		w.WriteLine(pluginutil.NoneValue)
+		return
+	}
+
+	// to print labels in the sorted order
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if i != 0 {
+			w.Write(LEVEL_0, "\t")
+		}
+		w.Write(LEVEL_0, "%s=%s\n", key, labels[key])
+	}
+}
+
+func printAnnotationsMultiline(w PrefixWriter, title string, annotations map[string]string) {
+	w.Write(LEVEL_0, "%s:\t", title)
+
+	if len(annotations) == 0 {
+		w.WriteLine(pluginutil.NoneValue)
+		return
+	}
+
+	// to print annotations in the sorted order
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	indent := "\t"
+	for i, key := range keys {
+		if i != 0 {
+			w.Write(LEVEL_0, indent)
+		}
+		value := strings.TrimSuffix(annotations[key], "\n")
+		if (len(value)+len(key)+2) > maxAnnotationLen || strings.Contains(value, "\n") {
+			w.Write(LEVEL_0, "%s:\n", key)
+			for _, s := range strings.Split(value, "\n") {
+				w.Write(LEVEL_0, "%s  %s\n", indent, shorten(s, maxAnnotationLen-2))
+			}
+		} else {
+			w.Write(LEVEL_0, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func shorten(s string, maxLength int) string {
+	if len(s) > maxLength {
+		return s[:maxLength] + "..."
+	}
+	return s
+}
+
+// describeEvents prints the events like kubectl describe, oldest first
+func describeEvents(w PrefixWriter, events []*corev1.Event) {
+	if len(events) == 0 {
+		w.Write(LEVEL_0, "Events:\t<none>\n")
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+
+	w.Write(LEVEL_0, "Events:\n  Type\tReason\tAge\tFrom\tMessage\n")
+	w.Write(LEVEL_1, "----\t------\t----\t----\t-------\n")
+	for _, e := range events {
+		age := pluginutil.UnknownValue
+		if seen := eventTime(e); !seen.IsZero() {
+			age = duration.HumanDuration(time.Since(seen))
+		}
+		from := e.Source.Component
+		if from == "" {
+			from = e.ReportingController
+		}
+		w.Write(LEVEL_1, "%v\t%v\t%s\t%v\t%v\n",
+			e.Type,
+			e.Reason,
+			age,
+			from,
+			strings.TrimSpace(e.Message),
+		)
+	}
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package diff
+
+import (
+	"sort"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// fleetDiff holds the differences between the managed clusters of two states of the fleet
+type fleetDiff struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Added   []string       `json:"added"`
+	Removed []string       `json:"removed"`
+	Changed []*clusterDiff `json:"changed"`
+}
+
+// clusterDiff holds the differences of a managed cluster present in both states of the fleet
+type clusterDiff struct {
+	Name        string    `json:"name"`
+	Labels      []*change `json:"labels,omitempty"`
+	Annotations []*change `json:"annotations,omitempty"`
+	Conditions  []*change `json:"conditions,omitempty"`
+	Versions    []*change `json:"versions,omitempty"`
+}
+
+// change is a change of the value of a key, an empty value meaning that the key is absent
+type change struct {
+	Key  string `json:"key"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+const (
+	kubernetesVersionKey = "kubernetes"
+	openShiftVersionKey  = "openshift"
+)
+
+func (d *clusterDiff) empty() bool {
+	return len(d.Labels) == 0 && len(d.Annotations) == 0 && len(d.Conditions) == 0 && len(d.Versions) == 0
+}
+
+// compare returns the differences between the managed clusters, all ordered by name
+func compare(from, to []*clusterv1.ManagedCluster) *fleetDiff {
+	diff := &fleetDiff{Added: []string{}, Removed: []string{}, Changed: []*clusterDiff{}}
+
+	fromByName := map[string]*clusterv1.ManagedCluster{}
+	for _, managedCluster := range from {
+		fromByName[managedCluster.Name] = managedCluster
+	}
+
+	toByName := map[string]*clusterv1.ManagedCluster{}
+	for _, managedCluster := range to {
+		toByName[managedCluster.Name] = managedCluster
+	}
+
+	for name := range fromByName {
+		if _, found := toByName[name]; !found {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	for name, toCluster := range toByName {
+		fromCluster, found := fromByName[name]
+		if !found {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+
+		if changed := compareCluster(fromCluster, toCluster); !changed.empty() {
+			diff.Changed = append(diff.Changed, changed)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+
+	return diff
+}
+
+func compareCluster(from, to *clusterv1.ManagedCluster) *clusterDiff {
+	// the last status sync annotation changes on every sync of the leaf hub, so it is not reported
+	fromAnnotations := withoutKey(from.GetAnnotations(), pluginutil.LastStatusSyncAnnotation)
+	toAnnotations := withoutKey(to.GetAnnotations(), pluginutil.LastStatusSyncAnnotation)
+
+	return &clusterDiff{
+		Name:        to.Name,
+		Labels:      compareMaps(from.GetLabels(), to.GetLabels()),
+		Annotations: compareMaps(fromAnnotations, toAnnotations),
+		Conditions:  compareMaps(conditionStatuses(from), conditionStatuses(to)),
+		Versions:    compareMaps(versions(from), versions(to)),
+	}
+}
+
+// compareMaps returns the changes of the keys of the maps, ordered by key
+func compareMaps(from, to map[string]string) []*change {
+	var changes []*change
+
+	for key, fromValue := range from {
+		if toValue := to[key]; toValue != fromValue {
+			changes = append(changes, &change{Key: key, From: fromValue, To: toValue})
+		}
+	}
+
+	for key, toValue := range to {
+		if _, found := from[key]; !found {
+			changes = append(changes, &change{Key: key, To: toValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+func conditionStatuses(managedCluster *clusterv1.ManagedCluster) map[string]string {
+	statuses := map[string]string{}
+	for _, condition := range managedCluster.Status.Conditions {
+		statuses[condition.Type] = string(condition.Status)
+	}
+
+	return statuses
+}
+
+func versions(managedCluster *clusterv1.ManagedCluster) map[string]string {
+	versions := map[string]string{}
+
+	if version := managedCluster.Status.Version.Kubernetes; version != "" {
+		versions[kubernetesVersionKey] = version
+	}
+	if version := pluginutil.GetOpenShiftVersion(managedCluster); version != "" {
+		versions[openShiftVersionKey] = version
+	}
+
+	return versions
+}
+
+func withoutKey(values map[string]string, key string) map[string]string {
+	if _, found := values[key]; !found {
+		return values
+	}
+
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if k != key {
+			result[k] = v
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func newManagedCluster(name string, labels, annotations map[string]string, available metav1.ConditionStatus,
+	kubernetesVersion string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1.ManagedClusterConditionAvailable, Status: available}},
+			Version:    clusterv1.ManagedClusterVersion{Kubernetes: kubernetesVersion},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := []*clusterv1.ManagedCluster{
+		newManagedCluster("cluster1", map[string]string{"environment": "dev", "cloud": "Amazon"},
+			map[string]string{pluginutil.LastStatusSyncAnnotation: "2022-01-01T00:00:00Z"}, metav1.ConditionTrue,
+			"v1.22.3"),
+		newManagedCluster("cluster2", nil, nil, metav1.ConditionTrue, "v1.22.3"),
+		newManagedCluster("cluster3", nil, nil, metav1.ConditionTrue, "v1.22.3"),
+	}
+
+	to := []*clusterv1.ManagedCluster{
+		newManagedCluster("cluster4", nil, nil, metav1.ConditionTrue, "v1.23.0"),
+		newManagedCluster("cluster3", nil, nil, metav1.ConditionTrue, "v1.22.3"),
+		newManagedCluster("cluster1", map[string]string{"environment": "prod", "vendor": "OpenShift"},
+			map[string]string{pluginutil.LastStatusSyncAnnotation: "2022-01-02T00:00:00Z"}, metav1.ConditionUnknown,
+			"v1.23.0"),
+	}
+
+	diff := compare(from, to)
+
+	if !reflect.DeepEqual(diff.Added, []string{"cluster4"}) {
+		t.Errorf("unexpected added managed clusters: %v", diff.Added)
+	}
+	if !reflect.DeepEqual(diff.Removed, []string{"cluster2"}) {
+		t.Errorf("unexpected removed managed clusters: %v", diff.Removed)
+	}
+
+	expected := []*clusterDiff{{
+		Name: "cluster1",
+		Labels: []*change{
+			{Key: "cloud", From: "Amazon"},
+			{Key: "environment", From: "dev", To: "prod"},
+			{Key: "vendor", To: "OpenShift"},
+		},
+		Conditions: []*change{{Key: clusterv1.ManagedClusterConditionAvailable, From: "True", To: "Unknown"}},
+		Versions:   []*change{{Key: kubernetesVersionKey, From: "v1.22.3", To: "v1.23.0"}},
+	}}
+
+	if !reflect.DeepEqual(diff.Changed, expected) {
+		t.Errorf("unexpected changed managed clusters: %+v", diff.Changed)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/snapshot"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	diffLong = templates.LongDesc(i18n.T(`
+		Compare the managed clusters of two snapshots, or of a snapshot and the live state of the fleet.
+
+		Reports the managed clusters that were added and removed, and for the managed clusters
+		present in both, the changes of their labels, annotations, condition statuses and
+		Kubernetes and OpenShift versions. If only one snapshot is specified, it is compared with
+		the live state of the fleet. Snapshots are created by the export command.`))
+
+	diffExample = templates.Examples(i18n.T(`
+		# Compare the snapshot in the directory 'before-upgrade' with the live state of the fleet
+		%[1]s diff before-upgrade
+
+		# Compare two snapshots
+		%[1]s diff before-upgrade after-upgrade
+
+		# Compare two snapshots in JSON output format
+		%[1]s diff before-upgrade after-upgrade -o json`))
+
+	errUnknownOutputFormat = errors.New("unknown output format")
+)
+
+const (
+	jsonOutput = "json"
+	yamlOutput = "yaml"
+)
+
+// Options contains the input to the diff command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	OutputFormat string
+
+	from   string
+	to     string
+	client *client.Client
+}
+
+// NewOptions returns an Options for the diff command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "diff" action, which compares two states of the managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "diff SNAPSHOT [SNAPSHOT] [(-o|--output=)json|yaml]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Compare the managed clusters of snapshots or of a snapshot and the live state"),
+		Long:                  diffLong,
+		Example:               fmt.Sprintf(diffExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", o.OutputFormat,
+		fmt.Sprintf("Output format. One of: %s.", strings.Join([]string{jsonOutput, yamlOutput}, "|")))
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Values(jsonOutput, yamlOutput)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) == 0 || len(args) > 2 {
+		return cmdutil.UsageErrorf(cmd, "one or two snapshot directories must be specified, got: %v", args)
+	}
+
+	o.from = args[0]
+	if len(args) > 1 {
+		o.to = args[1]
+		return nil
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	switch o.OutputFormat {
+	case "", jsonOutput, yamlOutput:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, allowed formats are: %s", errUnknownOutputFormat, o.OutputFormat,
+			strings.Join([]string{jsonOutput, yamlOutput}, ","))
+	}
+}
+
+// Run performs the diff operation.
+func (o *Options) Run() error {
+	fromName, from, err := o.fromSnapshot(o.from)
+	if err != nil {
+		return err
+	}
+
+	var (
+		toName string
+		to     []*clusterv1.ManagedCluster
+	)
+
+	if o.to != "" {
+		toName, to, err = o.fromSnapshot(o.to)
+	} else {
+		toName, to, err = o.fromLive()
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := compare(from, to)
+	diff.From, diff.To = fromName, toName
+
+	switch o.OutputFormat {
+	case jsonOutput:
+		data, err := json.MarshalIndent(diff, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+	case yamlOutput:
+		data, err := yaml.Marshal(diff)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.Out, string(data))
+	default:
+		printDiff(o.Out, diff)
+	}
+
+	return nil
+}
+
+// fromSnapshot returns a description of the snapshot in the directory and its managed clusters
+func (o *Options) fromSnapshot(dir string) (string, []*clusterv1.ManagedCluster, error) {
+	name := dir
+	if metadata, err := snapshot.ReadMetadata(dir); err == nil {
+		name = fmt.Sprintf("%s (exported at %s)", dir, metadata.Time.Format(time.RFC3339))
+	}
+
+	objs, err := snapshot.GetResourceObjects(dir, client.ManagedClustersPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	managedClusters, err := pluginutil.ToManagedClusters(objs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, managedClusters, nil
+}
+
+// fromLive returns a description of the live state of the fleet and its managed clusters
+func (o *Options) fromLive() (string, []*clusterv1.ManagedCluster, error) {
+	managedClusters, err := o.client.ListManagedClusters(context.TODO())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("live (%s)", o.client.URL()), managedClusters, nil
+}
+
+func printDiff(out io.Writer, diff *fleetDiff) {
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", diff.From, diff.To)
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		fmt.Fprintln(out, "No differences found")
+		return
+	}
+
+	if len(diff.Added) > 0 {
+		fmt.Fprintf(out, "\nAdded managed clusters (%d):\n", len(diff.Added))
+		for _, name := range diff.Added {
+			fmt.Fprintf(out, "  + %s\n", name)
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		fmt.Fprintf(out, "\nRemoved managed clusters (%d):\n", len(diff.Removed))
+		for _, name := range diff.Removed {
+			fmt.Fprintf(out, "  - %s\n", name)
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		fmt.Fprintf(out, "\nChanged managed clusters (%d):\n", len(diff.Changed))
+		for _, changed := range diff.Changed {
+			fmt.Fprintf(out, "  ~ %s\n", changed.Name)
+			printChanges(out, "label", changed.Labels)
+			printChanges(out, "annotation", changed.Annotations)
+			printChanges(out, "condition", changed.Conditions)
+			printChanges(out, "version", changed.Versions)
+		}
+	}
+}
+
+func printChanges(out io.Writer, kind string, changes []*change) {
+	for _, c := range changes {
+		fmt.Fprintf(out, "      %s %s: %s -> %s\n", kind, c.Key, pluginutil.ValueOrNone(c.From), pluginutil.ValueOrNone(c.To))
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package edit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/cmd/util/editor"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	editLong = templates.LongDesc(i18n.T(`
+		Edit resources from the default editor.
+
+		The edit command allows you to directly edit resources you can retrieve via the
+		get command.
+		It will open the editor defined by your KUBE_EDITOR, or EDITOR environment variables,
+		or fall back to 'vi' for Linux or 'notepad' for Windows.
+		You can edit multiple resources, although changes are applied one at a time.
+
+		Only labels, annotations and spec (including taints) can be changed. When saved, a merge
+		patch of these fields is sent to the Non-K8s API. If an error occurs while updating,
+		the editor is reopened with the relevant failures.`))
+
+	editExample = templates.Examples(i18n.T(`
+		# Edit the managed cluster named 'mycluster'
+		kubectl-mc edit managedcluster mycluster
+
+		# Edit the managed clusters named 'mycluster1' and 'mycluster2' using nano
+		KUBE_EDITOR="nano" kubectl-mc edit managedclusters mycluster1 mycluster2
+
+		# Edit the policy named 'mypolicy' in the namespace 'default'
+		kubectl-mc edit policy mypolicy -n default`))
+
+	errNotFound         = errors.New("not found")
+	errEditCancelled    = errors.New("edit cancelled, no valid changes were saved")
+	errImmutableChanged = errors.New("only labels, annotations and spec can be edited")
+	errNameChanged      = errors.New("the name and the namespace of an edited object cannot be changed")
+)
+
+const (
+	editHeader = `Please edit the object below. Lines beginning with a '#' will be ignored,
+and an empty file will abort the edit. If an error occurs while saving this file will be
+reopened with the relevant failures.
+`
+)
+
+// editorEnvs are the environment variables used to find the editor, in order of precedence
+var editorEnvs = []string{"KUBE_EDITOR", "EDITOR"}
+
+// Options contains the input to the edit command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	client    *client.Client
+	registry  *registry.Registry
+	resource  *registry.Resource
+	namespace string
+	names     []string
+}
+
+// NewOptions returns an Options for the edit command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		registry:      resourceRegistry,
+	}
+}
+
+// NewCmd creates a command object for the "edit" action, which edits one or more resources in the default editor.
+func NewCmd(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourceRegistry)
+
+	cmd := &cobra.Command{
+		Use:                   "edit TYPE NAME [NAME...]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Edit resources in the default editor"),
+		Long:                  editLong,
+		Example:               editExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.ValidArgsFunction = completion.ResourceTypesAndNames(clientFactory, resourceRegistry, registry.VerbPatch, true)
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.resource, o.names, err = o.registry.ResourceFromArgs(args, registry.VerbPatch)
+	if err != nil {
+		return err
+	}
+
+	if o.resource.Namespaced() {
+		o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if len(o.names) == 0 {
+		return cmdutil.UsageErrorf(cmd, "at least one NAME is required")
+	}
+
+	return nil
+}
+
+// Run performs the edit operation.
+func (o *Options) Run() error {
+	originals, err := o.fetchObjects()
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := (&printers.YAMLPrinter{}).PrintObj(toEditedObject(originals), buf); err != nil {
+		return fmt.Errorf("unable to print objects: %w", err)
+	}
+
+	edit := editor.NewDefaultEditor(editorEnvs)
+	content := buf.Bytes()
+	var editErrors []string
+
+	for {
+		buf := &bytes.Buffer{}
+		writeComments(buf, editHeader)
+		if len(editErrors) > 0 {
+			writeComments(buf, strings.Join(editErrors, "\n"))
+			fmt.Fprintln(buf, "#")
+		}
+		buf.Write(content)
+
+		edited, file, err := edit.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), ".yaml", buf)
+		if err != nil {
+			return fmt.Errorf("unable to launch the editor: %w", err)
+		}
+		os.Remove(file)
+
+		edited = stripComments(edited)
+		if len(bytes.TrimSpace(edited)) == 0 {
+			fmt.Fprintln(o.ErrOut, "Edit cancelled, saved file was empty.")
+			return nil
+		}
+
+		if bytes.Equal(edited, stripComments(content)) {
+			if len(editErrors) > 0 {
+				return errEditCancelled
+			}
+			fmt.Fprintln(o.ErrOut, "Edit cancelled, no changes made.")
+			return nil
+		}
+
+		content = edited
+		editErrors = o.applyEdits(originals, edited)
+		if len(editErrors) == 0 {
+			return nil
+		}
+	}
+}
+
+// applyEdits patches every changed object and returns the errors that should be shown in the reopened editor
+func (o *Options) applyEdits(originals []*unstructured.Unstructured, edited []byte) []string {
+	editedObjects, err := parseEditedObjects(edited)
+	if err != nil {
+		return []string{fmt.Sprintf("The edited file had a syntax error: %v", err)}
+	}
+
+	editedByKey := make(map[types.NamespacedName]*unstructured.Unstructured, len(editedObjects))
+	for _, editedObject := range editedObjects {
+		editedByKey[objectKey(editedObject)] = editedObject
+	}
+
+	var editErrors []string
+
+	for _, original := range originals {
+		editedObject, found := editedByKey[objectKey(original)]
+		if !found {
+			editErrors = append(editErrors, fmt.Sprintf("%s %q was not valid:\n* %v",
+				o.resource.Path, original.GetName(), errNameChanged))
+			continue
+		}
+
+		patch, err := createPatch(original, editedObject)
+		if err != nil {
+			editErrors = append(editErrors, fmt.Sprintf("%s %q was not valid:\n* %v",
+				o.resource.Path, original.GetName(), err))
+			continue
+		}
+
+		if string(patch) == "{}" {
+			fmt.Fprintf(o.Out, "%s/%s skipped\n", o.resource.Name(), original.GetName())
+			continue
+		}
+
+		if _, err := o.client.Patch(context.TODO(), o.resource.Path, original.GetNamespace(), original.GetName(),
+			types.MergePatchType, patch); err != nil {
+			editErrors = append(editErrors, fmt.Sprintf("%s %q could not be patched:\n* %v",
+				o.resource.Path, original.GetName(), err))
+			continue
+		}
+
+		// the patched object becomes the new original, so that it is not patched again if the editor is reopened
+		*original = *editedObject
+		fmt.Fprintf(o.Out, "%s/%s edited\n", o.resource.Name(), original.GetName())
+	}
+
+	return editErrors
+}
+
+// fetchObjects returns the objects with the names, in the namespace if the resource is namespaced
+func (o *Options) fetchObjects() ([]*unstructured.Unstructured, error) {
+	objs, err := o.client.List(context.TODO(), o.resource.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	objsByKey := make(map[types.NamespacedName]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		objsByKey[objectKey(obj)] = obj
+	}
+
+	originals := make([]*unstructured.Unstructured, 0, len(o.names))
+	for _, name := range o.names {
+		obj, found := objsByKey[types.NamespacedName{Namespace: o.namespace, Name: name}]
+		if !found {
+			return nil, fmt.Errorf("%s %q %w", o.resource.Path, name, errNotFound)
+		}
+		originals = append(originals, obj)
+	}
+
+	return originals, nil
+}
+
+// objectKey returns the namespace and the name of the object
+func objectKey(obj *unstructured.Unstructured) types.NamespacedName {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+// createPatch computes a merge patch of the mutable fields, failing if any other field was changed
+func createPatch(original, edited *unstructured.Unstructured) ([]byte, error) {
+	originalMutable, originalImmutable := splitMutableFields(original)
+	editedMutable, editedImmutable := splitMutableFields(edited)
+
+	if !equality.Semantic.DeepEqual(originalImmutable, editedImmutable) {
+		return nil, fmt.Errorf("%w, changed: %s", errImmutableChanged,
+			strings.Join(changedFields(originalImmutable, editedImmutable), ", "))
+	}
+
+	originalJSON, err := runtime.Encode(unstructured.UnstructuredJSONScheme, originalMutable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+
+	editedJSON, err := runtime.Encode(unstructured.UnstructuredJSONScheme, editedMutable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+
+	patch, err := jsonpatch.CreateMergePatch(originalJSON, editedJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch: %w", err)
+	}
+
+	return patch, nil
+}
+
+// splitMutableFields splits the object into its mutable fields (labels, annotations and spec) and the rest
+func splitMutableFields(obj *unstructured.Unstructured) (*unstructured.Unstructured, map[string]interface{}) {
+	immutable := runtime.DeepCopyJSON(obj.Object)
+	mutable := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	for _, path := range [][]string{{"metadata", "labels"}, {"metadata", "annotations"}, {"spec"}} {
+		value, found, _ := unstructured.NestedFieldNoCopy(immutable, path...)
+		if !found {
+			continue
+		}
+		//nolint:errcheck
+		unstructured.SetNestedField(mutable.Object, value, path...)
+		unstructured.RemoveNestedField(immutable, path...)
+	}
+
+	return mutable, immutable
+}
+
+func changedFields(original, edited map[string]interface{}) []string {
+	keys := map[string]struct{}{}
+	for key := range original {
+		keys[key] = struct{}{}
+	}
+	for key := range edited {
+		keys[key] = struct{}{}
+	}
+
+	var changed []string
+
+	for key := range keys {
+		if key == "metadata" {
+			originalMetadata, _ := original[key].(map[string]interface{})
+			editedMetadata, _ := edited[key].(map[string]interface{})
+			for _, field := range changedFields(originalMetadata, editedMetadata) {
+				changed = append(changed, "metadata."+field)
+			}
+			continue
+		}
+		if !equality.Semantic.DeepEqual(original[key], edited[key]) {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// toEditedObject returns the single object to edit or a list of all the objects to edit
+func toEditedObject(objs []*unstructured.Unstructured) runtime.Object {
+	if len(objs) == 1 {
+		return objs[0]
+	}
+
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"kind":       "List",
+			"apiVersion": "v1",
+			"metadata":   map[string]interface{}{},
+		},
+	}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj)
+	}
+
+	return list
+}
+
+func parseEditedObjects(edited []byte) ([]*unstructured.Unstructured, error) {
+	editedJSON, err := yaml.YAMLToJSON(edited)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := runtime.Decode(unstructured.UnstructuredJSONScheme, editedJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if !meta.IsListType(obj) {
+		unstructuredObj, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T", obj)
+		}
+		return []*unstructured.Unstructured{unstructuredObj}, nil
+	}
+
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		unstructuredObj, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T", item)
+		}
+		objs = append(objs, unstructuredObj)
+	}
+
+	return objs, nil
+}
+
+func writeComments(w io.Writer, comments string) {
+	for _, line := range strings.Split(strings.TrimSuffix(comments, "\n"), "\n") {
+		if line == "" {
+			fmt.Fprintln(w, "#")
+			continue
+		}
+		fmt.Fprintf(w, "# %s\n", line)
+	}
+}
+
+// stripComments removes the comment lines, starting with '#' in the first column, such as the header written by
+// the command. Indented lines starting with '#' are kept, since they may be lines of block scalars.
+func stripComments(content []byte) []byte {
+	buf := &bytes.Buffer{}
+
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("#")) {
+			continue
+		}
+		buf.Write(line)
+	}
+
+	return buf.Bytes()
+}
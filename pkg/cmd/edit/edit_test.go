@@ -0,0 +1,35 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package edit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripComments(t *testing.T) {
+	// a line longer than the default buffer of a bufio.Scanner
+	long := strings.Repeat("x", 100*1024)
+
+	content := "# Please edit the object below.\n#\n" +
+		"metadata:\n" +
+		"  annotations:\n" +
+		"    script: |\n" +
+		"      # not a comment\n" +
+		"      echo done\n" +
+		"    long: " + long + "\n" +
+		"spec: {}"
+
+	expected := "metadata:\n" +
+		"  annotations:\n" +
+		"    script: |\n" +
+		"      # not a comment\n" +
+		"      echo done\n" +
+		"    long: " + long + "\n" +
+		"spec: {}"
+
+	if stripped := string(stripComments([]byte(content))); stripped != expected {
+		t.Errorf("unexpected content without comments:\n%.300s", stripped)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	eventsLong = templates.LongDesc(i18n.T(`
+		Display the events related to managed clusters, reported by all the leaf hubs.
+
+		The events of a managed cluster are the events of the managed cluster object and the
+		events in the namespace of the managed cluster on its leaf hub, e.g. of its lease and its
+		addons. If no CLUSTER is specified, the events of all the managed clusters are displayed.
+		With -w, new events are printed as they occur.`))
+
+	eventsExample = templates.Examples(i18n.T(`
+		# List the events of all managed clusters
+		%[1]s events
+
+		# List the warning events of the managed cluster 'mycluster' in the last hour
+		%[1]s events mycluster --types=Warning --since=1h
+
+		# Follow the events of the managed cluster 'mycluster'
+		%[1]s events mycluster -w`))
+
+	errInvalidType = errors.New("invalid --types")
+)
+
+const (
+	managedClusterKind = "ManagedCluster"
+	pollInterval       = 5 * time.Second
+)
+
+// Options contains the input to the events command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	Since     time.Duration
+	Types     []string
+	Watch     bool
+	NoHeaders bool
+
+	client       *client.Client
+	resourcePath string
+	cluster      string
+
+	// printed holds the times the printed events were last seen by their keys, so that polling and watching print
+	// only the new events. The deleted events are forgotten.
+	printed map[string]time.Time
+}
+
+// flushWriter is a writer that buffers rows until flushed, like a tab writer
+type flushWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// NewOptions returns an Options for the events command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourcePath string) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		resourcePath:  resourcePath,
+		printed:       map[string]time.Time{},
+	}
+}
+
+// NewCmd creates a command object for the "events" action, which lists the events related to managed clusters.
+// The resource path is the path of events in Non-K8s API.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourcePath string) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourcePath)
+
+	cmd := &cobra.Command{
+		Use:                   "events [CLUSTER] [--since DURATION] [--types TYPES] [-w]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Display the events related to managed clusters"),
+		Long:                  eventsLong,
+		Example:               fmt.Sprintf(eventsExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.Since, "since", o.Since, "If non-zero, only show the events that occurred in this duration (e.g. 1h).")
+	cmd.Flags().StringSliceVar(&o.Types, "types", o.Types, "If present, only show the events of these types. One or more of: Normal|Warning.")
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing the events, watch for new events.")
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When true, don't print headers.")
+
+	cmd.ValidArgsFunction = completion.ManagedClusterNames(clientFactory, false)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("types",
+		completion.Values(corev1.EventTypeNormal, corev1.EventTypeWarning)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) > 1 {
+		return cmdutil.UsageErrorf(cmd, "at most one CLUSTER may be specified, got: %v", args)
+	}
+
+	if len(args) == 1 {
+		o.cluster = args[0]
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	for _, eventType := range o.Types {
+		if eventType != corev1.EventTypeNormal && eventType != corev1.EventTypeWarning {
+			return fmt.Errorf("%w: %q, must be one of Normal|Warning", errInvalidType, eventType)
+		}
+	}
+
+	return nil
+}
+
+// Run performs the events operation.
+func (o *Options) Run() error {
+	events, err := o.list()
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 && !o.Watch {
+		fmt.Fprintln(o.ErrOut, "No events found")
+		return nil
+	}
+
+	w := printers.GetNewTabWriter(o.Out)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tHUB\tMESSAGE")
+	}
+
+	o.printEvents(w, events)
+
+	if !o.Watch {
+		return nil
+	}
+
+	return o.follow(w)
+}
+
+// follow prints the new events from the watch stream, or else by polling the events
+func (o *Options) follow(w flushWriter) error {
+	for {
+		_, err := o.client.Watch(context.TODO(), o.resourcePath,
+			func(event *client.WatchEvent) (bool, error) {
+				// the deleted events are not sent again, so they are forgotten
+				if event.Type == watch.Deleted {
+					o.forget(event.Object.GetUID())
+					return false, nil
+				}
+
+				converted, err := toEvent(event.Object)
+				if err != nil {
+					return false, err
+				}
+
+				o.printEvents(w, o.filter([]*corev1.Event{converted}))
+
+				return false, nil
+			})
+		if errors.Is(err, client.ErrWatchNotSupported) {
+			return o.poll(w)
+		}
+		if err != nil {
+			return err
+		}
+		// the watch stream was ended by the server, so the events are watched again after an interval. The events
+		// are listed in between, to print the events that were missed and to forget the deleted ones
+		time.Sleep(pollInterval)
+
+		if err := o.printListed(w); err != nil {
+			return err
+		}
+	}
+}
+
+// poll prints the new events by listing the events every poll interval
+func (o *Options) poll(w flushWriter) error {
+	for {
+		time.Sleep(pollInterval)
+
+		if err := o.printListed(w); err != nil {
+			return err
+		}
+	}
+}
+
+// printListed lists the events and prints the new ones. The events that are not listed anymore are not listed
+// again, so they are forgotten.
+func (o *Options) printListed(w flushWriter) error {
+	events, err := o.list()
+	if err != nil {
+		return err
+	}
+
+	o.printEvents(w, events)
+
+	listed := sets.NewString()
+	for _, event := range events {
+		listed.Insert(eventKey(event))
+	}
+	for key := range o.printed {
+		if !listed.Has(key) {
+			delete(o.printed, key)
+		}
+	}
+
+	return nil
+}
+
+// forget forgets all the printed occurrences of the event
+func (o *Options) forget(uid types.UID) {
+	prefix := string(uid) + "/"
+	for key := range o.printed {
+		if strings.HasPrefix(key, prefix) {
+			delete(o.printed, key)
+		}
+	}
+}
+
+// list returns the events that match the filters, ordered by the time they were last seen
+func (o *Options) list() ([]*corev1.Event, error) {
+	objs, err := o.client.List(context.TODO(), o.resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*corev1.Event, 0, len(objs))
+	for _, obj := range objs {
+		event, err := toEvent(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	events = o.filter(events)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return lastSeen(events[i]).Before(lastSeen(events[j]))
+	})
+
+	return events, nil
+}
+
+func (o *Options) filter(events []*corev1.Event) []*corev1.Event {
+	types := sets.NewString(o.Types...)
+	filtered := make([]*corev1.Event, 0, len(events))
+
+	for _, event := range events {
+		if o.cluster != "" && !isClusterEvent(event, o.cluster) {
+			continue
+		}
+		if types.Len() > 0 && !types.Has(event.Type) {
+			continue
+		}
+		if o.Since > 0 && time.Since(lastSeen(event)) > o.Since {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+
+	return filtered
+}
+
+// printEvents prints the events that were not printed yet. The printed events older than --since are forgotten,
+// since they are filtered out anyway.
+func (o *Options) printEvents(w flushWriter, events []*corev1.Event) {
+	if o.Since > 0 {
+		for key, seen := range o.printed {
+			if time.Since(seen) > o.Since {
+				delete(o.printed, key)
+			}
+		}
+	}
+
+	for _, event := range events {
+		key := eventKey(event)
+		if _, found := o.printed[key]; found {
+			continue
+		}
+		o.printed[key] = lastSeen(event)
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", formatLastSeen(event), event.Type, event.Reason,
+			involvedObject(event), pluginutil.ValueOrNone(pluginutil.GetLeafHubName(event)),
+			strings.Join(strings.Fields(event.Message), " "))
+	}
+
+	//nolint:errcheck
+	w.Flush()
+}
+
+// eventKey returns the key of an occurrence of the event
+func eventKey(event *corev1.Event) string {
+	return fmt.Sprintf("%s/%d/%s", event.UID, event.Count, lastSeen(event).Format(time.RFC3339Nano))
+}
+
+// isClusterEvent returns true if the event is about the managed cluster or an object in its namespace
+func isClusterEvent(event *corev1.Event, cluster string) bool {
+	if event.InvolvedObject.Kind == managedClusterKind && event.InvolvedObject.Name == cluster {
+		return true
+	}
+
+	return event.InvolvedObject.Namespace == cluster || event.Namespace == cluster
+}
+
+func toEvent(obj *unstructured.Unstructured) (*corev1.Event, error) {
+	event := &corev1.Event{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, event); err != nil {
+		return nil, fmt.Errorf("failed to convert %s to event: %w", obj.GetName(), err)
+	}
+
+	return event, nil
+}
+
+// lastSeen returns the time the event was last seen, falling back to the times of series events and creation
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func formatLastSeen(event *corev1.Event) string {
+	seen := lastSeen(event)
+	if seen.IsZero() {
+		return pluginutil.NoneValue
+	}
+
+	return duration.HumanDuration(time.Since(seen))
+}
+
+func involvedObject(event *corev1.Event) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+func TestPrintEventsForgetsOldEvents(t *testing.T) {
+	o := NewOptions(client.NewFactory(nil), genericclioptions.NewTestIOStreamsDiscard(), "events")
+	o.Since = time.Hour
+
+	newEvent := func(reason string, seen time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{UID: types.UID("uid-" + reason)},
+			Reason:        reason,
+			LastTimestamp: metav1.NewTime(seen),
+		}
+	}
+
+	old := newEvent("old", time.Now().Add(-2*time.Hour))
+	recent := newEvent("recent", time.Now().Add(-time.Minute))
+
+	var out bytes.Buffer
+	w := printers.GetNewTabWriter(&out)
+
+	o.printEvents(w, []*corev1.Event{old, recent})
+	o.printEvents(w, []*corev1.Event{recent})
+
+	if count := strings.Count(out.String(), "recent"); count != 1 {
+		t.Errorf("expected the recent event printed once, got %d times:\n%s", count, out.String())
+	}
+
+	if _, found := o.printed[eventKey(old)]; found {
+		t.Errorf("expected the event older than --since forgotten")
+	}
+
+	if _, found := o.printed[eventKey(recent)]; !found {
+		t.Errorf("expected the recent event remembered")
+	}
+}
+
+func TestForgetDeletedEvent(t *testing.T) {
+	o := NewOptions(client.NewFactory(nil), genericclioptions.NewTestIOStreamsDiscard(), "events")
+
+	newEvent := func(uid string, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{UID: types.UID(uid)},
+			Count:         count,
+			LastTimestamp: metav1.NewTime(time.Now().Add(time.Duration(count) * time.Second)),
+		}
+	}
+
+	first, second, other := newEvent("uid-1", 1), newEvent("uid-1", 2), newEvent("uid-10", 1)
+
+	var out bytes.Buffer
+	o.printEvents(printers.GetNewTabWriter(&out), []*corev1.Event{first, second, other})
+
+	o.forget("uid-1")
+
+	if len(o.printed) != 1 {
+		t.Errorf("expected all the occurrences of the deleted event forgotten, got %v", o.printed)
+	}
+
+	if _, found := o.printed[eventKey(other)]; !found {
+		t.Errorf("expected the other event remembered")
+	}
+}
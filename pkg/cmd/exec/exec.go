@@ -0,0 +1,416 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	osexec "os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/tools/clientcmd"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+var (
+	execLong = templates.LongDesc(i18n.T(`
+		Execute a command against each of the selected managed clusters.
+
+		The managed clusters are selected by their names, or by a label selector (-l) or --all,
+		optionally restricted to a leaf hub by --hub. For each managed cluster, a kubeconfig is
+		obtained through the hub of hubs, as by the kubeconfig command, and the command is run
+		locally with the KUBECONFIG environment variable set to it and MCL_CLUSTER set to the name
+		of the managed cluster. Up to --parallel commands run concurrently.
+
+		Every line of output is prefixed by the name of the managed cluster, and a summary of the
+		exit codes is printed at the end. The command fails if it failed on any managed cluster.
+
+		The kubeconfigs are written to a temporary directory, which is removed when the commands
+		end, or when the plugin is interrupted or terminated, which kills the commands.`))
+
+	execExample = templates.Examples(i18n.T(`
+		# List the nodes of all the production managed clusters
+		%[1]s exec -l environment=prod -- kubectl get nodes
+
+		# Get the version of the managed clusters 'cluster1' and 'cluster2'
+		%[1]s exec cluster1 cluster2 -- kubectl version --short
+
+		# Run a script against all the managed clusters of the leaf hub 'hub1', 10 at a time
+		%[1]s exec --all --hub hub1 --parallel 10 -- ./check-cluster.sh`))
+
+	errCommandFailed       = errors.New("the command failed")
+	errNoMatchingResources = errors.New("no matching resources found")
+	errTimedOut            = errors.New("timed out")
+	errInterrupted         = errors.New("interrupted")
+)
+
+const (
+	defaultParallel = 5
+	clusterEnvVar   = "MCL_CLUSTER"
+	// failedExitCode is reported for the managed clusters the command could not be started against
+	failedExitCode = -1
+)
+
+// Options contains the input to the exec command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	All           bool
+	LeafHub       string
+	Parallel      int
+	Timeout       time.Duration
+
+	client   *client.Client
+	names    []string
+	command  []string
+	selector labels.Selector
+}
+
+// result is the result of running the command against a managed cluster
+type result struct {
+	cluster  string
+	exitCode int
+	err      error
+}
+
+// NewOptions returns an Options for the exec command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Parallel: defaultParallel,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "exec" action, which runs a command against managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "exec (NAME... | (-l label | --all) [--hub HUB]) [--parallel N] -- COMMAND [args...]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Execute a command against each of the selected managed clusters"),
+		Long:                  execLong,
+		Example:               fmt.Sprintf(execExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Select all the managed clusters.")
+	cmd.Flags().StringVar(&o.LeafHub, "hub", o.LeafHub, "If present, only select the managed clusters of this leaf hub.")
+	cmd.Flags().IntVar(&o.Parallel, "parallel", o.Parallel, "The maximum number of managed clusters to run the command against concurrently.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "If non-zero, the time after which the command is killed on each managed cluster.")
+
+	cmd.ValidArgsFunction = completion.ManagedClusterNames(clientFactory, true)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector", completion.ManagedClusterLabelSelectors(clientFactory)))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("hub", completion.HubNames(clientFactory)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		o.names, o.command = args[:dash], args[dash:]
+	} else {
+		o.names = args
+	}
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if len(o.command) == 0 {
+		return cmdutil.UsageErrorf(cmd, "a command must be specified after --")
+	}
+
+	if len(o.names) == 0 && o.LabelSelector == "" && !o.All {
+		return cmdutil.UsageErrorf(cmd, "one of NAME, -l or --all must be specified")
+	}
+
+	if len(o.names) > 0 && (o.LabelSelector != "" || o.All || o.LeafHub != "") {
+		return cmdutil.UsageErrorf(cmd, "NAME cannot be combined with -l, --all or --hub")
+	}
+
+	if o.Parallel < 1 {
+		return cmdutil.UsageErrorf(cmd, "--parallel must be at least 1")
+	}
+
+	return nil
+}
+
+// Run performs the exec operation. If the plugin is interrupted or terminated, the commands are killed and the
+// kubeconfigs of the managed clusters are removed.
+func (o *Options) Run() error {
+	clusters, err := o.selectClusters()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	tempDir, err := ioutil.TempDir("", "kubectl-mc-exec-")
+	if err != nil {
+		return fmt.Errorf("unable to create directory for kubeconfigs: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var (
+		outMutex sync.Mutex
+		wg       sync.WaitGroup
+	)
+
+	results := make([]*result, len(clusters))
+	semaphore := make(chan struct{}, o.Parallel)
+
+	for i, cluster := range clusters {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			// the command is not run against the remaining managed clusters
+			results[i] = &result{cluster: cluster, exitCode: failedExitCode, err: errInterrupted}
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, cluster string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i] = o.runOn(ctx, cluster, tempDir, &outMutex)
+		}(i, cluster)
+	}
+
+	wg.Wait()
+
+	return o.printSummary(results)
+}
+
+// selectClusters returns the names of the managed clusters selected by name, or by the selector and the leaf hub
+func (o *Options) selectClusters() ([]string, error) {
+	managedClusters, err := o.client.ListManagedClusters(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*clusterv1.ManagedCluster, len(managedClusters))
+	for _, managedCluster := range managedClusters {
+		existing[managedCluster.Name] = managedCluster
+	}
+
+	if len(o.names) > 0 {
+		for _, name := range o.names {
+			if _, found := existing[name]; !found {
+				return nil, fmt.Errorf("%w: managed cluster %q", errNoMatchingResources, name)
+			}
+		}
+
+		return sets.NewString(o.names...).List(), nil
+	}
+
+	selected := sets.NewString()
+	for name, managedCluster := range existing {
+		if !o.selector.Matches(labels.Set(managedCluster.GetLabels())) {
+			continue
+		}
+		if o.LeafHub != "" && pluginutil.GetLeafHubName(managedCluster) != o.LeafHub {
+			continue
+		}
+		selected.Insert(name)
+	}
+
+	if selected.Len() == 0 {
+		return nil, errNoMatchingResources
+	}
+
+	return selected.List(), nil
+}
+
+// runOn runs the command with a kubeconfig of the managed cluster, prefixing its output by the cluster name
+func (o *Options) runOn(ctx context.Context, cluster, tempDir string, outMutex *sync.Mutex) *result {
+	stdout := newPrefixWriter(o.Out, fmt.Sprintf("[%s] ", cluster), outMutex)
+	stderr := newPrefixWriter(o.ErrOut, fmt.Sprintf("[%s] ", cluster), outMutex)
+
+	defer stdout.Flush()
+	defer stderr.Flush()
+
+	parent := ctx
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	config, err := o.client.GetManagedClusterKubeconfig(ctx, cluster)
+	if err == nil {
+		config, err = pluginutil.RenameKubeconfig(config, cluster)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return &result{cluster: cluster, exitCode: failedExitCode, err: err}
+	}
+
+	kubeconfigFile := filepath.Join(tempDir, cluster+".kubeconfig")
+	if err := clientcmd.WriteToFile(*config, kubeconfigFile); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return &result{cluster: cluster, exitCode: failedExitCode, err: err}
+	}
+
+	//nolint:gosec
+	command := osexec.CommandContext(ctx, o.command[0], o.command[1:]...)
+	command.Env = append(os.Environ(), clientcmd.RecommendedConfigPathEnvVar+"="+kubeconfigFile,
+		clusterEnvVar+"="+cluster)
+	command.Stdout, command.Stderr = stdout, stderr
+
+	err = command.Run()
+
+	var exitErr *osexec.ExitError
+	switch {
+	case err == nil:
+		return &result{cluster: cluster}
+	case errors.As(err, &exitErr) && ctx.Err() == nil:
+		return &result{cluster: cluster, exitCode: exitErr.ExitCode()}
+	case parent.Err() != nil:
+		fmt.Fprintf(stderr, "error: %v\n", errInterrupted)
+		return &result{cluster: cluster, exitCode: failedExitCode, err: errInterrupted}
+	case ctx.Err() != nil:
+		err = fmt.Errorf("%w after %s", errTimedOut, o.Timeout)
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return &result{cluster: cluster, exitCode: failedExitCode, err: err}
+	default:
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return &result{cluster: cluster, exitCode: failedExitCode, err: err}
+	}
+}
+
+func (o *Options) printSummary(results []*result) error {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].cluster < results[j].cluster
+	})
+
+	failed := 0
+
+	fmt.Fprintln(o.Out)
+
+	w := printers.GetNewTabWriter(o.Out)
+	fmt.Fprintln(w, "CLUSTER\tEXIT CODE\tERROR")
+
+	for _, r := range results {
+		message := pluginutil.NoneValue
+		if r.err != nil {
+			message = r.err.Error()
+		}
+		if r.exitCode != 0 {
+			failed++
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\n", r.cluster, r.exitCode, message)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w on %d of %d managed clusters", errCommandFailed, failed, len(results))
+	}
+
+	return nil
+}
+
+// prefixWriter writes every line prefixed, holding the mutex so that lines of concurrent writers do not interleave
+type prefixWriter struct {
+	out    io.Writer
+	prefix string
+	mutex  *sync.Mutex
+	buffer []byte
+}
+
+func newPrefixWriter(out io.Writer, prefix string, mutex *sync.Mutex) *prefixWriter {
+	return &prefixWriter{out: out, prefix: prefix, mutex: mutex}
+}
+
+// Write writes the complete lines of p, buffering the last line until it is complete or flushed
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		if err := w.writeLine(w.buffer[:i+1]); err != nil {
+			return 0, err
+		}
+
+		w.buffer = w.buffer[i+1:]
+	}
+}
+
+// Flush writes the buffered incomplete line, if any
+func (w *prefixWriter) Flush() {
+	if len(w.buffer) == 0 {
+		return
+	}
+
+	//nolint:errcheck
+	w.writeLine(append(w.buffer, '\n'))
+	w.buffer = nil
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+
+	return err
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/snapshot"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	exportLong = templates.LongDesc(i18n.T(`
+		Export the state of the fleet to a snapshot directory.
+
+		Writes every resource fetched from the hub of hubs into a file of the directory,
+		together with the time of the export, the URL of the hub and the version of the plugin.
+		The snapshot can be read later by the --from-snapshot flag of the get, describe and
+		summary commands, e.g. for post-incident analysis or to share the state of the fleet.`))
+
+	exportExample = templates.Examples(i18n.T(`
+		# Export the state of the fleet to the directory 'fleet-snapshot'
+		%[1]s export -o fleet-snapshot
+
+		# List the managed clusters of the snapshot
+		%[1]s get --from-snapshot fleet-snapshot
+
+		# Describe the managed cluster 'mycluster' of the snapshot
+		%[1]s describe managedcluster mycluster --from-snapshot fleet-snapshot`))
+
+	errNoResourcesExported = errors.New("no resources were exported")
+)
+
+// Options contains the input to the export command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	OutputDir string
+
+	client        *client.Client
+	resourcePaths []string
+}
+
+// NewOptions returns an Options for the export command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourcePaths []string) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		resourcePaths: resourcePaths,
+	}
+}
+
+// NewCmd creates a command object for the "export" action, which exports the state of the fleet to a snapshot.
+// The resource paths are the paths in Non-K8s API of the resources to export.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourcePaths []string) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourcePaths)
+
+	cmd := &cobra.Command{
+		Use:                   "export -o DIR",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Export the state of the fleet to a snapshot directory"),
+		Long:                  exportLong,
+		Example:               fmt.Sprintf(exportExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.OutputDir, "output", "o", o.OutputDir, "The directory to write the snapshot to.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if o.OutputDir == "" {
+		return cmdutil.UsageErrorf(cmd, "an output directory must be specified with -o")
+	}
+
+	return nil
+}
+
+// Run performs the export operation. Every object of the resources is written, following the continue tokens
+// of lists returned in pages. A resource that fails to be fetched is reported and skipped, so that a partial
+// snapshot is still written.
+func (o *Options) Run() error {
+	metadata := &snapshot.Metadata{
+		Time:          time.Now().UTC(),
+		HubURL:        o.client.URL(),
+		PluginVersion: version.Version,
+	}
+
+	for _, resourcePath := range o.resourcePaths {
+		body, err := o.listResource(resourcePath)
+		if err != nil {
+			fmt.Fprintf(o.ErrOut, "warning: unable to export %s: %v\n", resourcePath, err)
+			continue
+		}
+
+		if err := snapshot.WriteResource(o.OutputDir, resourcePath, body); err != nil {
+			return err
+		}
+
+		metadata.Resources = append(metadata.Resources, resourcePath)
+		fmt.Fprintf(o.Out, "%s exported\n", resourcePath)
+	}
+
+	if len(metadata.Resources) == 0 {
+		return errNoResourcesExported
+	}
+
+	return snapshot.WriteMetadata(o.OutputDir, metadata)
+}
+
+// listResource returns all the objects of the resource path as a JSON array, the way Non-K8s API returns whole
+// lists
+func (o *Options) listResource(resourcePath string) ([]byte, error) {
+	objs, err := o.client.List(context.TODO(), resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]map[string]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		items = append(items, obj.Object)
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s: %w", resourcePath, err)
+	}
+
+	return body, nil
+}
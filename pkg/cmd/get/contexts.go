@@ -0,0 +1,272 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package get
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// contextColumn is the column of the context the rows were listed from, prepended to the tables of the contexts
+var contextColumn = metav1.TableColumnDefinition{
+	Name:        "Context",
+	Type:        "string",
+	Description: "The kubeconfig context of the hub of hubs the object was listed from.",
+}
+
+var errNoRowObject = errors.New("the table has a row without object")
+
+// contextObjects holds the objects listed from a context and the related objects of the resource, or the error
+// of listing them
+type contextObjects struct {
+	objs    []runtime.Object
+	related []*unstructured.Unstructured
+	err     error
+}
+
+// getObjectsOfContexts lists the objects from the hubs of hubs of the contexts concurrently, and merges them in
+// the order of the contexts. The objects are annotated with their context, and for human-readable output the
+// tables of the contexts are merged into a table with a CONTEXT column. If the tables of the contexts have
+// different columns, e.g. of different versions of Non-K8s API, the objects of their rows are converted to tables
+// client-side instead, or else the tables with different columns are printed separately. The failures of the
+// contexts are returned with the objects of the other contexts, to be returned after the objects are printed,
+// unless every context failed, which is an error.
+func (o *Options) getObjectsOfContexts(withNamespace bool) ([]runtime.Object, []error, error) {
+	results := make([]contextObjects, len(o.contextNames))
+
+	var wg sync.WaitGroup
+
+	for i, contextName := range o.contextNames {
+		wg.Add(1)
+
+		go func(result *contextObjects, contextName string) {
+			defer wg.Done()
+			result.objs, result.related, result.err = o.getObjectsOfContext(contextName, withNamespace)
+		}(&results[i], contextName)
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	for i := range results {
+		if results[i].err != nil {
+			errs = append(errs, fmt.Errorf("context %q: %w", o.contextNames[i], results[i].err))
+		}
+	}
+
+	if len(errs) == len(o.contextNames) {
+		return nil, nil, utilerrors.NewAggregate(errs)
+	}
+
+	if o.IsHumanReadablePrinter && o.resource.Cells != nil && !sameColumns(results) {
+		for i := range results {
+			if results[i].err != nil {
+				continue
+			}
+
+			objs, err := rowObjects(results[i].objs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to merge the tables of the contexts, context %q: %w",
+					o.contextNames[i], err)
+			}
+
+			results[i].objs, err = o.toContextObjects(objs, results[i].related, o.contextNames[i], withNamespace)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return mergeTables(results), errs, nil
+}
+
+// sameColumns returns true if the tables of the contexts that did not fail have the same columns
+func sameColumns(results []contextObjects) bool {
+	var columns []metav1.TableColumnDefinition
+
+	for _, result := range results {
+		for _, obj := range result.objs {
+			table, isTable := obj.(*metav1.Table)
+			if result.err != nil || !isTable {
+				continue
+			}
+
+			if columns == nil {
+				columns = table.ColumnDefinitions
+			} else if !reflect.DeepEqual(columns, table.ColumnDefinitions) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// rowObjects returns the objects, with the tables replaced by the objects of their rows
+func rowObjects(objs []runtime.Object) ([]runtime.Object, error) {
+	result := make([]runtime.Object, 0, len(objs))
+
+	for _, obj := range objs {
+		table, isTable := obj.(*metav1.Table)
+		if !isTable {
+			result = append(result, obj)
+			continue
+		}
+
+		for _, row := range table.Rows {
+			if row.Object.Object == nil {
+				return nil, errNoRowObject
+			}
+			result = append(result, row.Object.Object)
+		}
+	}
+
+	return result, nil
+}
+
+// mergeTables returns the objects of the contexts that did not fail, with their tables merged into the first
+// table with the same columns
+func mergeTables(results []contextObjects) []runtime.Object {
+	var (
+		objs   []runtime.Object
+		merged []*metav1.Table
+	)
+
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+
+		for _, obj := range result.objs {
+			table, isTable := obj.(*metav1.Table)
+			if !isTable {
+				objs = append(objs, obj)
+				continue
+			}
+
+			if into := tableWithColumns(merged, table.ColumnDefinitions); into != nil {
+				into.Rows = append(into.Rows, table.Rows...)
+				continue
+			}
+
+			merged = append(merged, table)
+			objs = append(objs, table)
+		}
+	}
+
+	return objs
+}
+
+// tableWithColumns returns the first of the tables with the columns, nil if none
+func tableWithColumns(tables []*metav1.Table, columns []metav1.TableColumnDefinition) *metav1.Table {
+	for _, table := range tables {
+		if reflect.DeepEqual(table.ColumnDefinitions, columns) {
+			return table
+		}
+	}
+
+	return nil
+}
+
+// getObjectsOfContext lists the objects from the hub of hubs of the context, annotated with the context, and the
+// related objects of the resource. For human-readable output, the objects are converted to a table with a
+// CONTEXT column.
+func (o *Options) getObjectsOfContext(contextName string,
+	withNamespace bool) ([]runtime.Object, []*unstructured.Unstructured, error) {
+	c, err := o.clientFactory.NewClientForContext(contextName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objs, err := o.getObjects(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !o.IsHumanReadablePrinter {
+		for _, obj := range objs {
+			annotateContext(obj, contextName)
+		}
+
+		return objs, nil, nil
+	}
+
+	related, err := o.getRelatedObjects(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objs, err = o.toContextObjects(objs, related, contextName, withNamespace)
+
+	return objs, related, err
+}
+
+// toContextObjects converts the objects of the context to a table with a CONTEXT column, or annotates them with
+// the context if they cannot be converted
+func (o *Options) toContextObjects(objs []runtime.Object, related []*unstructured.Unstructured, contextName string,
+	withNamespace bool) ([]runtime.Object, error) {
+	objs = toTable(o.resource, objs, related, withNamespace)
+
+	for i, obj := range objs {
+		table, isTable, err := asTable(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTable {
+			annotateContext(obj, contextName)
+			continue
+		}
+
+		objs[i] = withContextColumn(table, contextName)
+	}
+
+	return objs, nil
+}
+
+// withContextColumn returns the table with the context prepended to its columns and its rows, and the objects of
+// its rows annotated with the context
+func withContextColumn(table *metav1.Table, contextName string) *metav1.Table {
+	// the table is not deep copied, since the cells of the tables converted client-side may not be JSON values
+	result := *table
+	result.ColumnDefinitions = append([]metav1.TableColumnDefinition{contextColumn}, table.ColumnDefinitions...)
+	result.Rows = append([]metav1.TableRow(nil), table.Rows...)
+
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		row.Cells = append([]interface{}{contextName}, row.Cells...)
+
+		if row.Object.Object != nil {
+			annotateContext(row.Object.Object, contextName)
+		}
+	}
+
+	return &result
+}
+
+// annotateContext annotates the object with the context it was listed from
+func annotateContext(obj runtime.Object, contextName string) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	annotations := accessor.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[pluginutil.ContextAnnotation] = contextName
+	accessor.SetAnnotations(annotations)
+}
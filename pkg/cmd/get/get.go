@@ -19,27 +19,30 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-//nolint
+// nolint
 package get
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/snapshot"
 	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -48,7 +51,6 @@ import (
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
 	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/rest"
 	watchtools "k8s.io/client-go/tools/watch"
 	kubectlget "k8s.io/kubectl/pkg/cmd/get"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -77,43 +79,82 @@ type Options struct {
 
 	LabelSelector string
 	FieldSelector string
+	AllNamespaces bool
+	Namespace     string
 
 	ServerPrint bool
 
 	NoHeaders      bool
 	Sort           bool
 	IgnoreNotFound bool
+	GroupBy        string
+	View           string
+	FromSnapshot   string
+	Contexts       []string
+	AllContexts    bool
 
 	genericclioptions.IOStreams
-	configFlags *genericclioptions.ConfigFlags
-
-	nonk8sAPIURL string
-	token        string
-	mapping      *meta.RESTMapping
-	resourcePath string
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	client   *client.Client
+	registry *registry.Registry
+	resource *registry.Resource
+	names    []string
+	selector labels.Selector
+	// contextNames are the kubeconfig contexts to query if --contexts or --all-contexts is specified
+	contextNames []string
 }
 
 var (
 	getLong = templates.LongDesc(i18n.T(`
-		Display one or many managed clusters.
+		Display one or many resources. A NAME requires a resource type. If no resource type is specified,
+		managed clusters are displayed.
 
-		Prints a table of the most important information about the specified managed clusters.
-		You can filter the list using a label selector and the --selector flag.
+		Prints a table of the most important information about the specified resources.
+		You can filter the list using a label selector and the --selector flag. If the desired
+		resource type is namespaced you will only see results in your current namespace unless
+		you pass --all-namespaces.
 
 		By specifying the output as 'template' and providing a Go template as the value
-		of the --template flag, you can filter the attributes of the fetched managed clusters.`))
+		of the --template flag, you can filter the attributes of the fetched resources.`))
 
 	getExample = templates.Examples(i18n.T(`
 		# List all managed clusters in ps output format
 		kubectl-mc get
 
 		# List a single managed cluster with specified NAME in ps output format
-		kubectl-mc get mycluster
+		kubectl-mc get managedcluster mycluster
 
 		# List a single managed cluster in JSON output format
-		kubectl-mc get -o json mycluster`))
+		kubectl-mc get -o json managedcluster mycluster
+
+		# List all policies with their compliance state across all leaf hubs
+		kubectl-mc get policies
+
+		# List all placements in the namespace 'mynamespace' with the number of the clusters they selected
+		kubectl-mc get placements -n mynamespace
+
+		# List all placement rules in all the namespaces with the clusters they selected
+		kubectl-mc get placementrules -A
+
+		# List all managed clusters grouped by their leaf hub, sorted by name within every leaf hub
+		kubectl-mc get --group-by=hub --sort-by=.metadata.name
+
+		# List all managed clusters grouped by their Kubernetes version
+		kubectl-mc get --group-by='{.status.version.kubernetes}'
+
+		# List all managed clusters by the built-in view 'health', grouped by their leaf hub
+		kubectl-mc get --view=health
 
-	errStatusNotOK = errors.New("response status not HTTP OK")
+		# List all policies of a snapshot exported to the directory 'fleet-snapshot'
+		kubectl-mc get policies -A --from-snapshot fleet-snapshot
+
+		# List all managed clusters of the hubs of hubs of the kubeconfig contexts 'east' and 'west'
+		kubectl-mc get --contexts=east,west
+
+		# List all policies of the hubs of hubs of all the kubeconfig contexts, sorted by name
+		kubectl-mc get policies -A --all-contexts --sort-by=.metadata.name`))
 )
 
 const (
@@ -122,33 +163,33 @@ const (
 )
 
 // NewOptions returns a Options with default chunk size 500.
-func NewOptions(parent string, configFlags *genericclioptions.ConfigFlags,
-	streams genericclioptions.IOStreams, mapping *meta.RESTMapping,
-	resourcePath string) *Options {
+// The default resource of the registry is used if no resource type is specified.
+func NewOptions(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams, resourceRegistry *registry.Registry) *Options {
 	return &Options{
 		PrintFlags: kubectlget.NewGetPrintFlags(),
 		CmdParent:  parent,
 
-		configFlags:  configFlags,
-		IOStreams:    streams,
-		ChunkSize:    cmdutil.DefaultChunkSize,
-		ServerPrint:  true,
-		mapping:      mapping,
-		resourcePath: resourcePath,
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		ChunkSize:     cmdutil.DefaultChunkSize,
+		ServerPrint:   true,
+		registry:      resourceRegistry,
 	}
 }
 
 // NewCmd creates a command object for the generic "get" action, which
 // retrieves one or more resources from a server.
-func NewCmd(parent string, f cmdutil.Factory, configFlags *genericclioptions.ConfigFlags,
-	streams genericclioptions.IOStreams, mapping *meta.RESTMapping, resourcePath, resourceNamePlural string) *cobra.Command {
-	o := NewOptions(parent, configFlags, streams, mapping, resourcePath)
+func NewCmd(parent string, f cmdutil.Factory, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams, resourceRegistry *registry.Registry) *cobra.Command {
+	o := NewOptions(parent, clientFactory, streams, resourceRegistry)
 
 	cmd := &cobra.Command{
-		Use: fmt.Sprintf("get [(-o|--output=)%s] [NAME | -l label] [flags]",
+		Use: fmt.Sprintf("get [(-o|--output=)%s] [TYPE [NAME] | -l label] [flags]",
 			strings.Join(o.PrintFlags.AllowedFormats(), "|")),
 		DisableFlagsInUseLine: true,
-		Short:                 i18n.T("Display one or many " + resourceNamePlural),
+		Short:                 i18n.T("Display one or many resources"),
 		Long:                  getLong,
 		Example:               getExample,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -161,9 +202,15 @@ func NewCmd(parent string, f cmdutil.Factory, configFlags *genericclioptions.Con
 
 	o.PrintFlags.AddFlags(cmd)
 
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing/getting the requested object, watch for changes.")
 	cmd.Flags().BoolVar(&o.OutputWatchEvents, "output-watch-events", o.OutputWatchEvents, "Output watch event objects when --watch is used. Existing objects are output as initial ADDED events.")
 	cmd.Flags().BoolVar(&o.IgnoreNotFound, "ignore-not-found", o.IgnoreNotFound, "If the requested object does not exist the command will return exit code 0.")
+	cmd.Flags().StringVar(&o.GroupBy, "group-by", o.GroupBy, "If non-empty, print a table for every group of the objects, with a heading and a count. Objects are grouped by their leaf hub if 'hub', by the value of a JSONPath expression (e.g. '{.status.version.kubernetes}') or else by the value of a label key.")
+	cmd.Flags().StringVar(&o.View, pluginconfig.ViewFlag, o.View, "If present, print the objects by the named view of the config file or the built-in view (e.g. health, versions, capacity), which sets the columns, the selector, the sort and the grouping unless they are specified by their flags. See 'views' for the available views.")
+	cmd.Flags().StringVar(&o.FromSnapshot, "from-snapshot", o.FromSnapshot, "If present, read the resources from the snapshot in this directory, created by the export command, instead of the hub.")
+	cmd.Flags().StringSliceVar(&o.Contexts, "contexts", o.Contexts, "If present, list the objects of the hubs of hubs of these kubeconfig contexts, comma separated, concurrently, and print them together with a CONTEXT column.")
+	cmd.Flags().BoolVar(&o.AllContexts, "all-contexts", o.AllContexts, "If present, list the objects of the hubs of hubs of all the kubeconfig contexts, as --contexts does.")
 	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector, "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
 	addOpenAPIPrintColumnFlags(cmd, o)
 	addServerPrintColumnFlags(cmd, o)
@@ -171,6 +218,18 @@ func NewCmd(parent string, f cmdutil.Factory, configFlags *genericclioptions.Con
 
 	// TODO replace with cmdutil.AddLabelSelectorFlagVar() in a latest version
 	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+
+	cmd.ValidArgsFunction = completion.ResourceTypes(resourceRegistry, registry.VerbList)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector",
+		completion.LabelSelectors(clientFactory, resourceRegistry, registry.VerbList)))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("sort-by", completion.SortByFields(clientFactory, resourceRegistry)))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(pluginconfig.ViewFlag, completion.ViewNames()))
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("contexts", completion.ContextNames(clientFactory.ConfigFlags())))
+
+	cmdutil.CheckErr(pluginconfig.MarkFlagSetting(cmd.Flags(), "output", pluginconfig.OutputKey))
+	cmdutil.CheckErr(pluginconfig.MarkFlagSetting(cmd.Flags(), "selector", pluginconfig.SelectorKey))
+	cmdutil.CheckErr(pluginconfig.MarkFlagSetting(cmd.Flags(), "label-columns", pluginconfig.ColumnsKey))
+
 	return cmd
 }
 
@@ -178,6 +237,26 @@ func NewCmd(parent string, f cmdutil.Factory, configFlags *genericclioptions.Con
 func (o *Options) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
 	var err error
 
+	o.resource, o.names, err = o.registry.ResourceFromArgs(args, registry.VerbList)
+	if err != nil {
+		return err
+	}
+
+	// the selector setting selects managed clusters, so it does not filter the other resources
+	if o.resource != o.registry.Default() && pluginconfig.IsFlagFromSetting(cmd.Flags(), "selector") {
+		o.LabelSelector = ""
+	}
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.Namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
 	sortBy, err := cmd.Flags().GetString("sort-by")
 	if err != nil {
 		return err
@@ -255,17 +334,22 @@ func (o *Options) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string)
 		fmt.Fprintf(o.IOStreams.ErrOut, "warning: --%s requested, --%s will be ignored\n", useOpenAPIPrintColumnFlagLabel, useServerPrintColumns)
 	}
 
-	config, err := o.configFlags.ToRawKubeConfigLoader().RawConfig()
-	if err != nil {
-		return err
+	if len(o.FromSnapshot) > 0 {
+		return nil
 	}
 
-	o.nonk8sAPIURL, err = pluginutil.GetNonK8sAPIURL(config)
-	if err != nil {
+	if len(o.Contexts) > 0 || o.AllContexts {
+		kubeconfig, err := o.configFlags.ToRawKubeConfigLoader().RawConfig()
+		if err != nil {
+			return err
+		}
+
+		o.contextNames, err = pluginutil.ContextNames(kubeconfig, o.Contexts, o.AllContexts)
+
 		return err
 	}
 
-	o.token, err = pluginutil.GetToken(config)
+	o.client, err = o.clientFactory.NewClient()
 	if err != nil {
 		return err
 	}
@@ -281,13 +365,22 @@ func (o *Options) Validate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("--show-labels option cannot be used with %s printer", outputOption)
 		}
 	}
+	if len(o.GroupBy) > 0 && !o.IsHumanReadablePrinter {
+		// the custom columns are printed as a table for every group, like the human readable output
+		outputOption := cmd.Flags().Lookup("output").Value.String()
+		if !strings.HasPrefix(outputOption, "custom-columns") {
+			return fmt.Errorf("--group-by option cannot be used with %s printer", outputOption)
+		}
+	}
+	if len(o.Contexts) > 0 && o.AllContexts {
+		return cmdutil.UsageErrorf(cmd, "--contexts and --all-contexts cannot be used together")
+	}
+	if (len(o.Contexts) > 0 || o.AllContexts) && len(o.FromSnapshot) > 0 {
+		return cmdutil.UsageErrorf(cmd, "--contexts and --all-contexts cannot be used with --from-snapshot")
+	}
 	if o.OutputWatchEvents && !o.Watch {
 		return cmdutil.UsageErrorf(cmd, "--output-watch-events option can only be used with --watch")
 	}
-
-	if len(args) > 0 {
-		return cmdutil.UsageErrorf(cmd, "currently, only getting all the clusters is supported")
-	}
 	return nil
 }
 
@@ -393,60 +486,115 @@ func NewRuntimeSorter(objects []runtime.Object, sortBy string) *RuntimeSorter {
 	}
 }
 
-func (o *Options) transformRequests(req *rest.Request) {
-	// We need full objects if printing with openapi columns
-	if o.PrintWithOpenAPICols {
-		return
-	}
-	if !o.ServerPrint || !o.IsHumanReadablePrinter {
-		return
+// getBody returns the body of the resource from the snapshot if --from-snapshot is specified, or else from
+// Non-K8s API. A nil body is returned if there is nothing to print. Lists returned in pages are joined into
+// a single JSON array.
+func (o *Options) getBody(c *client.Client) ([]byte, error) {
+	if len(o.FromSnapshot) > 0 {
+		return snapshot.ReadResource(o.FromSnapshot, o.resource.Path)
 	}
 
-	req.SetHeader("Accept", strings.Join([]string{
-		fmt.Sprintf("application/json;as=Table;v=%s;g=%s", metav1.SchemeGroupVersion.Version, metav1.GroupName),
-		fmt.Sprintf("application/json;as=Table;v=%s;g=%s", metav1beta1.SchemeGroupVersion.Version, metav1beta1.GroupName),
-		"application/json",
-	}, ","))
+	var (
+		items []interface{}
+		table *metav1.Table
+	)
 
-	// if sorting, ensure we receive the full object in order to introspect its fields via jsonpath
-	if o.Sort {
-		req.Param("includeObject", "Object")
+	continueToken := ""
+
+	for {
+		body, err := o.getPage(c, continueToken)
+		if body == nil || err != nil {
+			return body, err
+		}
+
+		kind, nextToken := pageOf(body)
+		if continueToken == "" && nextToken == "" {
+			return body, nil
+		}
+
+		switch {
+		case kind == "Table":
+			page := &metav1.Table{}
+			if err := json.Unmarshal(body, page); err != nil {
+				return nil, fmt.Errorf("unable to decode the table: %w", err)
+			}
+
+			// the rows of the next pages are appended to the first page, which has the column definitions
+			if table == nil {
+				table = page
+			} else {
+				table.Rows = append(table.Rows, page.Rows...)
+			}
+		case strings.HasSuffix(kind, "List"):
+			list := &unstructured.UnstructuredList{}
+			if err := list.UnmarshalJSON(body); err != nil {
+				return nil, fmt.Errorf("unable to decode the list: %w", err)
+			}
+
+			for _, item := range list.Items {
+				items = append(items, item.Object)
+			}
+		default:
+			return body, nil
+		}
+
+		continueToken = nextToken
+		if continueToken != "" {
+			continue
+		}
+
+		if table != nil {
+			table.Continue = ""
+			return json.Marshal(table)
+		}
+
+		return json.Marshal(items)
 	}
 }
 
-// Run performs the get operation.
-// TODO: remove the need to pass these arguments, like other commands.
-func (o *Options) Run(cmd *cobra.Command, args []string) error {
-	//TODO
-	// if o.Watch {
-	//	return o.watch(f, cmd, args)
-	//}
+// pageOf returns the kind and the continue token of the body if it is a list or a table, empty for an array
+func pageOf(body []byte) (string, string) {
+	page := &struct {
+		metav1.TypeMeta `json:",inline"`
+		metav1.ListMeta `json:"metadata,omitempty"`
+	}{}
 
-	chunkSize := o.ChunkSize
-	if o.Sort {
-		// TODO(juanvallejo): in the future, we could have the client use chunking
-		// to gather all results, then sort them all at the end to reduce server load.
-		chunkSize = 0
+	if err := json.Unmarshal(body, page); err != nil {
+		return "", ""
 	}
 
-	// TODO fix
-	_ = chunkSize
+	return page.Kind, page.Continue
+}
 
-	client, err := createClient()
-	if err != nil {
-		return fmt.Errorf("unable to create client: %w", err)
+// getPage returns the body of the resource from Non-K8s API of the client, continued by the continue token if
+// not empty
+func (o *Options) getPage(c *client.Client, continueToken string) ([]byte, error) {
+	tables := o.ServerPrint && o.IsHumanReadablePrinter
+
+	query := url.Values{}
+	if continueToken != "" {
+		query.Set("continue", continueToken)
+	}
+	// if sorting or grouping, ensure we receive the full objects of the rows to introspect their fields via jsonpath,
+	// and if merging the tables of contexts, to convert them client-side if their columns differ
+	if tables && (o.Sort || len(o.GroupBy) > 0 || len(o.contextNames) > 0) {
+		query.Set("includeObject", "Object")
 	}
 
-	req, err := http.NewRequestWithContext(context.TODO(), "GET",
-		fmt.Sprintf("%s/multicloud/hub-of-hubs-nonk8s-api/%s", o.nonk8sAPIURL, o.resourcePath), nil)
+	resourcePath := o.resource.Path
+	if len(query) > 0 {
+		resourcePath = fmt.Sprintf("%s?%s", resourcePath, query.Encode())
+	}
+
+	req, err := c.NewRequest(context.TODO(), "GET", resourcePath, nil)
 	if err != nil {
-		return fmt.Errorf("unable to create request: %w", err)
+		return nil, err
 	}
 
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", o.token))
 
-	if o.ServerPrint {
+	// like kubectl, tables are requested only for human-readable output
+	if tables {
 		req.Header.Add("Accept", strings.Join([]string{
 			fmt.Sprintf("application/json;as=Table;v=%s;g=%s", metav1.SchemeGroupVersion.Version, metav1.GroupName),
 			"application/json",
@@ -455,32 +603,150 @@ func (o *Options) Run(cmd *cobra.Command, args []string) error {
 		req.Header.Add("Accept", "application/json")
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("got error: %w", err)
+	body, err := c.Do(req)
+	if o.IgnoreNotFound && client.IsNotFound(err) {
+		return nil, nil
 	}
-	defer resp.Body.Close()
 
-	if o.IgnoreNotFound && resp.StatusCode != http.StatusNotFound {
-		return nil
+	if err != nil {
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %d", errStatusNotOK, resp.StatusCode)
+	return body, nil
+}
+
+// getObjects returns the objects of the resource from the client (or the snapshot), filtered by the namespace,
+// the names and the selector. No objects are returned if there is nothing to print.
+// Names of no object fail with client.ErrNotFound, unless --ignore-not-found is specified.
+func (o *Options) getObjects(c *client.Client) ([]runtime.Object, error) {
+	body, err := o.getBody(c)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, nil
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	objs, err := pluginutil.GetObjects(body)
 	if err != nil {
-		return fmt.Errorf("unable to read response body: %w", err)
+		return nil, fmt.Errorf("unable to get objects from the body: %w", err)
+	}
+
+	if o.resource.Namespaced() && !o.AllNamespaces {
+		objs = filterByNamespace(objs, o.Namespace)
+	}
+	if len(o.names) > 0 {
+		var missingNames []string
+		if objs, missingNames = filterByNames(objs, o.names); len(missingNames) > 0 && !o.IgnoreNotFound {
+			return nil, fmt.Errorf("%w: %s %q", client.ErrNotFound, o.resource.Path, missingNames)
+		}
+	}
+	if !o.selector.Empty() {
+		objs = filterBySelector(objs, o.selector)
+	}
+
+	return objs, nil
+}
+
+// getRelatedObjects returns the objects of the related resource of the resource from the client (or the snapshot),
+// needed to print the resource as a table. A related resource that is not served, or not in the snapshot, has no
+// objects.
+func (o *Options) getRelatedObjects(c *client.Client) ([]*unstructured.Unstructured, error) {
+	if o.resource.RelatedPath == "" || o.resource.Cells == nil {
+		return nil, nil
+	}
+
+	if len(o.FromSnapshot) > 0 {
+		objs, err := snapshot.GetResourceObjects(o.FromSnapshot, o.resource.RelatedPath)
+		if errors.Is(err, snapshot.ErrResourceNotInSnapshot) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return toUnstructured(objs), nil
+	}
+
+	objs, err := c.List(context.TODO(), o.resource.RelatedPath)
+	if client.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return objs, err
+}
+
+// toUnstructured returns the unstructured objects, with the items of the lists
+func toUnstructured(objs []runtime.Object) []*unstructured.Unstructured {
+	var result []*unstructured.Unstructured
+
+	for _, obj := range objs {
+		switch typedObj := obj.(type) {
+		case *unstructured.Unstructured:
+			result = append(result, typedObj)
+		case *unstructured.UnstructuredList:
+			for i := range typedObj.Items {
+				result = append(result, &typedObj.Items[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// Run performs the get operation.
+// TODO: remove the need to pass these arguments, like other commands.
+func (o *Options) Run(cmd *cobra.Command, args []string) error {
+	//TODO
+	// if o.Watch {
+	//	return o.watch(f, cmd, args)
+	//}
+
+	chunkSize := o.ChunkSize
+	if o.Sort {
+		// TODO(juanvallejo): in the future, we could have the client use chunking
+		// to gather all results, then sort them all at the end to reduce server load.
+		chunkSize = 0
+	}
+
+	// TODO fix
+	_ = chunkSize
+
+	withNamespace := o.resource.Namespaced() && o.AllNamespaces
+
+	var (
+		objs []runtime.Object
+		// the failures of some of the contexts, returned after the objects of the others are printed
+		contextErrs []error
+		err         error
+	)
+
+	if len(o.contextNames) > 0 {
+		objs, contextErrs, err = o.getObjectsOfContexts(withNamespace)
+	} else {
+		objs, err = o.getObjects(o.client)
 	}
 
-	objs, err := getObjects(body)
 	if err != nil {
-		return fmt.Errorf("unable to get objects from the body: %w", err)
+		return err
 	}
 
 	if !o.IsHumanReadablePrinter {
-		return o.printGeneric(objs)
+		if len(o.GroupBy) > 0 {
+			err = o.printGenericGroups(objs)
+		} else {
+			err = o.printGeneric(objs)
+		}
+		return utilerrors.NewAggregate(append([]error{err}, contextErrs...))
+	}
+
+	if len(o.contextNames) == 0 {
+		related, err := o.getRelatedObjects(o.client)
+		if err != nil {
+			return err
+		}
+
+		objs = toTable(o.resource, objs, related, withNamespace)
 	}
 
 	allErrs := []error{}
@@ -491,38 +757,52 @@ func (o *Options) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var positioner OriginalPositioner
-	if o.Sort {
-		sorter := NewRuntimeSorter(objs, sorting)
-		if err := sorter.Sort(); err != nil {
+	groups := []*objectGroup{{objs: objs}}
+	if len(o.GroupBy) > 0 {
+		if groups, err = groupObjects(objs, o.GroupBy); err != nil {
 			return err
 		}
-		positioner = sorter
 	}
 
-	var printer printers.ResourcePrinter
-
 	// track if we write any output
 	trackingWriter := &trackingWriterWrapper{Delegate: o.Out}
 	// output an empty line separating output
 	separatorWriter := &separatorWriterWrapper{Delegate: trackingWriter}
 
 	w := printers.GetNewTabWriter(separatorWriter)
-	printer, err = o.ToPrinter(o.mapping, nil, false, false)
 
-	if err != nil {
-		if !errs.Has(err.Error()) {
-			errs.Insert(err.Error())
-			allErrs = append(allErrs, err)
+	for _, group := range groups {
+		if len(o.GroupBy) > 0 {
+			fmt.Fprintf(separatorWriter, "%s: %s (%d)\n", o.GroupBy, group.value, group.count)
 		}
-	} else {
-		for ix := range objs {
+
+		var positioner OriginalPositioner
+		if o.Sort {
+			// the objects are sorted in a copy, since the positioner maps to the positions of the original objects
+			sorter := NewRuntimeSorter(append([]runtime.Object(nil), group.objs...), sorting)
+			if err := sorter.Sort(); err != nil {
+				return err
+			}
+			positioner = sorter
+		}
+
+		// a new printer is used for every group, so that the headers are printed for each group
+		printer, err := o.ToPrinter(o.resource.Mapping, nil, withNamespace && o.resource.Cells == nil, false)
+		if err != nil {
+			if !errs.Has(err.Error()) {
+				errs.Insert(err.Error())
+				allErrs = append(allErrs, err)
+			}
+			break
+		}
+
+		for ix := range group.objs {
 			var obj runtime.Object
 
 			if positioner != nil {
-				obj = objs[positioner.OriginalPosition(ix)]
+				obj = group.objs[positioner.OriginalPosition(ix)]
 			} else {
-				obj = objs[ix]
+				obj = group.objs[ix]
 			}
 
 			// ensure a versioned object is passed to the custom-columns printer
@@ -534,45 +814,14 @@ func (o *Options) Run(cmd *cobra.Command, args []string) error {
 
 			printer.PrintObj(obj, w)
 		}
+		w.Flush()
+		separatorWriter.SetReady(true)
 	}
-	w.Flush()
 	if trackingWriter.Written == 0 && !o.IgnoreNotFound && len(allErrs) == 0 {
 		fmt.Fprintln(o.ErrOut, "No resources found")
 	}
 
-	return utilerrors.NewAggregate(allErrs)
-}
-
-func getObjects(rawBytes []byte) ([]runtime.Object, error) {
-	var results []interface{}
-
-	err := json.Unmarshal(rawBytes, &results)
-	if err != nil {
-		var result interface{}
-		err := json.Unmarshal(rawBytes, &result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshall json: %w", err)
-		}
-		results = append(results, result)
-	}
-
-	var objects []runtime.Object
-
-	for _, result := range results {
-		resultData, err := json.Marshal(result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshall json: %w", err)
-		}
-
-		converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, resultData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode: %w", err)
-		}
-
-		objects = append(objects, converted)
-	}
-
-	return objects, nil
+	return utilerrors.NewAggregate(append(allErrs, contextErrs...))
 }
 
 type trackingWriterWrapper struct {
@@ -701,6 +950,27 @@ func (o *Options) watch(f cmdutil.Factory, cmd *cobra.Command, args []string) er
 	return nil
 }
 
+// printGenericGroups prints the objects of every group with a heading and a count, separated by empty lines
+func (o *Options) printGenericGroups(objects []runtime.Object) error {
+	groups, err := groupObjects(objects, o.GroupBy)
+	if err != nil {
+		return err
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(o.Out)
+		}
+		fmt.Fprintf(o.Out, "%s: %s (%d)\n", o.GroupBy, group.value, group.count)
+
+		if err := o.printGeneric(group.objs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (o *Options) printGeneric(objects []runtime.Object) error {
 	var errs []error
 
@@ -824,18 +1094,3 @@ func multipleGVKsRequestedForObjects(objects []runtime.Object) bool {
 	}
 	return false
 }
-
-func createClient() (*http.Client, error) {
-	tlsConfig := &tls.Config{
-		//nolint:gosec
-		InsecureSkipVerify: true,
-	}
-
-	tr := &http.Transport{TLSClientConfig: tlsConfig}
-	client := &http.Client{
-		Transport:     tr,
-		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
-	}
-
-	return client, nil
-}
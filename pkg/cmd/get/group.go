@@ -0,0 +1,178 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package get
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	kubectlget "k8s.io/kubectl/pkg/cmd/get"
+)
+
+const (
+	// groupByHub groups the objects by the leaf hub that reported them
+	groupByHub   = "hub"
+	noGroupValue = pluginutil.NoneValue
+)
+
+// objectGroup holds the objects to print for a value of --group-by. Table objects are split by their rows,
+// so count is the number of the rows or the objects in the group.
+type objectGroup struct {
+	value string
+	objs  []runtime.Object
+	count int
+
+	table *metav1.Table
+}
+
+// groupObjects groups the objects (or the rows of table objects) by the leaf hub, a JSONPath expression
+// or a label key, ordered by the value of the group
+func groupObjects(objs []runtime.Object, groupBy string) ([]*objectGroup, error) {
+	groupValue, err := newGroupValueFunc(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsByValue := map[string]*objectGroup{}
+	getGroup := func(value string) *objectGroup {
+		group, found := groupsByValue[value]
+		if !found {
+			group = &objectGroup{value: value}
+			groupsByValue[value] = group
+		}
+		return group
+	}
+
+	for _, obj := range objs {
+		table, isTable, err := asTable(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTable {
+			group := getGroup(groupValue(obj))
+			group.objs = append(group.objs, obj)
+			group.count++
+			continue
+		}
+
+		for _, row := range table.Rows {
+			group := getGroup(groupValue(row.Object.Object))
+			if group.table == nil {
+				group.table = &metav1.Table{ColumnDefinitions: table.ColumnDefinitions}
+				group.table.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("Table"))
+				group.objs = append(group.objs, group.table)
+			}
+			group.table.Rows = append(group.table.Rows, row)
+			group.count++
+		}
+	}
+
+	groups := make([]*objectGroup, 0, len(groupsByValue))
+	for _, group := range groupsByValue {
+		groups = append(groups, group)
+	}
+
+	// the objects with no value for the group are printed last
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].value == noGroupValue) != (groups[j].value == noGroupValue) {
+			return groups[j].value == noGroupValue
+		}
+		return groups[i].value < groups[j].value
+	})
+
+	return groups, nil
+}
+
+// newGroupValueFunc returns a function that returns the value of the group of an object
+func newGroupValueFunc(groupBy string) (func(runtime.Object) string, error) {
+	if groupBy == groupByHub {
+		return func(obj runtime.Object) string {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return noGroupValue
+			}
+			return valueOrNoGroup(pluginutil.GetLeafHubName(accessor))
+		}, nil
+	}
+
+	if strings.HasPrefix(groupBy, "{") || strings.HasPrefix(groupBy, ".") {
+		expression, err := kubectlget.RelaxedJSONPathExpression(groupBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --group-by expression %q: %w", groupBy, err)
+		}
+
+		parser := jsonpath.New("group-by").AllowMissingKeys(true)
+		if err := parser.Parse(expression); err != nil {
+			return nil, fmt.Errorf("invalid --group-by expression %q: %w", groupBy, err)
+		}
+
+		return func(obj runtime.Object) string {
+			unstructuredObj, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return noGroupValue
+			}
+			results, err := parser.FindResults(unstructuredObj.Object)
+			if err != nil || len(results) == 0 || len(results[0]) == 0 {
+				return noGroupValue
+			}
+			return valueOrNoGroup(fmt.Sprint(results[0][0].Interface()))
+		}, nil
+	}
+
+	return func(obj runtime.Object) string {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return noGroupValue
+		}
+		return valueOrNoGroup(accessor.GetLabels()[groupBy])
+	}, nil
+}
+
+// asTable returns the object as a table if it is a table, decoding the objects of the rows if needed
+func asTable(obj runtime.Object) (*metav1.Table, bool, error) {
+	if table, ok := obj.(*metav1.Table); ok {
+		return table, true, nil
+	}
+
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok || unstructuredObj.GetKind() != "Table" {
+		return nil, false, nil
+	}
+
+	table := &metav1.Table{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, table); err != nil {
+		return nil, false, fmt.Errorf("failed to decode table: %w", err)
+	}
+	table.SetGroupVersionKind(unstructuredObj.GroupVersionKind())
+
+	for i := range table.Rows {
+		row := &table.Rows[i]
+		if row.Object.Raw == nil || row.Object.Object != nil {
+			continue
+		}
+		converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, row.Object.Raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decode table row: %w", err)
+		}
+		row.Object.Object = converted
+	}
+
+	return table, true, nil
+}
+
+func valueOrNoGroup(value string) string {
+	if value == "" {
+		return noGroupValue
+	}
+
+	return value
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package get
+
+import (
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// namespaceColumnName is the name of the column that is shown only when listing across all namespaces
+const namespaceColumnName = "Namespace"
+
+// toTable converts the objects into a table with the columns of the resource, computed with the related objects
+// of the resource. The namespace column is omitted unless withNamespace is true.
+// If the objects are already tables or the resource has no columns, the objects are returned as is.
+func toTable(r *registry.Resource, objs []runtime.Object, related []*unstructured.Unstructured,
+	withNamespace bool) []runtime.Object {
+	if r.Cells == nil {
+		return objs
+	}
+
+	namespaceColumn := -1
+	table := &metav1.Table{}
+	table.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("Table"))
+
+	for i, column := range r.ColumnDefinitions {
+		if column.Name == namespaceColumnName && !withNamespace {
+			namespaceColumn = i
+			continue
+		}
+		table.ColumnDefinitions = append(table.ColumnDefinitions, column)
+	}
+
+	for _, obj := range objs {
+		unstructuredObj, ok := obj.(*unstructured.Unstructured)
+		if !ok || unstructuredObj.GetKind() == "Table" {
+			return objs
+		}
+
+		cells := r.Cells(unstructuredObj, related)
+		if namespaceColumn >= 0 && namespaceColumn < len(cells) {
+			cells = append(cells[:namespaceColumn:namespaceColumn], cells[namespaceColumn+1:]...)
+		}
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Object: unstructuredObj},
+		})
+	}
+
+	return []runtime.Object{table}
+}
+
+// filterByNamespace returns the objects in the namespace. The rows of tables returned by Non-K8s API are
+// filtered by the metadata of their objects, if included.
+func filterByNamespace(objs []runtime.Object, namespace string) []runtime.Object {
+	return filterObjects(objs, func(obj *unstructured.Unstructured) bool {
+		return obj.GetNamespace() == namespace
+	})
+}
+
+// filterBySelector returns the objects whose labels match the selector, filtering the rows of tables as well
+func filterBySelector(objs []runtime.Object, selector labels.Selector) []runtime.Object {
+	return filterObjects(objs, func(obj *unstructured.Unstructured) bool {
+		return selector.Matches(labels.Set(obj.GetLabels()))
+	})
+}
+
+// filterByNames returns the objects with the names, and the names of no object, sorted
+func filterByNames(objs []runtime.Object, names []string) ([]runtime.Object, []string) {
+	wanted := sets.NewString(names...)
+	found := sets.NewString()
+
+	filtered := filterObjects(objs, func(obj *unstructured.Unstructured) bool {
+		if !wanted.Has(obj.GetName()) {
+			return false
+		}
+
+		found.Insert(obj.GetName())
+		return true
+	})
+
+	return filtered, wanted.Difference(found).List()
+}
+
+// filterObjects returns the objects to keep. The rows of tables are kept if they do not include their objects.
+func filterObjects(objs []runtime.Object, keep func(*unstructured.Unstructured) bool) []runtime.Object {
+	filtered := make([]runtime.Object, 0, len(objs))
+
+	for _, obj := range objs {
+		if list, ok := obj.(*unstructured.UnstructuredList); ok {
+			filteredList := list.DeepCopy()
+			filteredList.Items = nil
+			for i := range list.Items {
+				if keep(&list.Items[i]) {
+					filteredList.Items = append(filteredList.Items, list.Items[i])
+				}
+			}
+			filtered = append(filtered, filteredList)
+			continue
+		}
+
+		unstructuredObj, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			filtered = append(filtered, obj)
+			continue
+		}
+
+		if unstructuredObj.GetKind() != "Table" {
+			if keep(unstructuredObj) {
+				filtered = append(filtered, obj)
+			}
+			continue
+		}
+
+		rows, _, _ := unstructured.NestedSlice(unstructuredObj.Object, "rows")
+		filteredRows := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			rowMap, ok := row.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rowObject, found, _ := unstructured.NestedMap(rowMap, "object")
+			if !found || keep(&unstructured.Unstructured{Object: rowObject}) {
+				filteredRows = append(filteredRows, row)
+			}
+		}
+
+		table := unstructuredObj.DeepCopy()
+		//nolint:errcheck
+		unstructured.SetNestedSlice(table.Object, filteredRows, "rows")
+		filtered = append(filtered, table)
+	}
+
+	return filtered
+}
+
+// Age returns the age of the object in the format used by kubectl
+func Age(obj *unstructured.Unstructured) string {
+	creationTimestamp := obj.GetCreationTimestamp()
+	if creationTimestamp.IsZero() {
+		return pluginutil.UnknownValue
+	}
+
+	return duration.HumanDuration(time.Since(creationTimestamp.Time))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package cmd_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update makes TestGetOutput write the golden files, run: go test ./pkg/cmd -run TestGetOutput -update
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+const (
+	testdataDir = "testdata"
+	fixturesDir = "fixtures"
+	goldenDir   = "get"
+)
+
+func TestGetOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "default", args: []string{}},
+		// a single object is printed as an object, not as a list
+		{name: "default-single", args: []string{"-l", "environment=dev"}},
+		{name: "default-no-server-print", args: []string{"--server-print=false"}},
+		{name: "default-policies", args: []string{"policies"}},
+		{name: "default-policies-all-namespaces", args: []string{"policies", "-A"}},
+		{name: "default-selector", args: []string{"-l", "environment=prod"}},
+		{name: "wide", args: []string{"-o", "wide"}},
+		{name: "wide-policies-all-namespaces", args: []string{"policies", "-A", "-o", "wide"}},
+		{name: "json", args: []string{"-o", "json"}},
+		{name: "json-single", args: []string{"-l", "environment=dev", "-o", "json"}},
+		{name: "yaml", args: []string{"-o", "yaml"}},
+		{name: "yaml-single", args: []string{"-l", "environment=dev", "-o", "yaml"}},
+		{name: "yaml-policies-all-namespaces", args: []string{"policies", "-A", "-o", "yaml"}},
+		{name: "name", args: []string{"-o", "name"}},
+		{name: "name-policies", args: []string{"policies", "-o", "name"}},
+		{name: "jsonpath", args: []string{"-o", "jsonpath={.items[*].metadata.name}"}},
+		{name: "jsonpath-single", args: []string{"-l", "environment=dev", "-o", "jsonpath={.metadata.labels.cloud}"}},
+		{
+			name: "go-template",
+			args: []string{"-o", `go-template={{range .items}}{{.metadata.name}} {{.metadata.labels.cloud}}{{"\n"}}{{end}}`},
+		},
+		{
+			name: "custom-columns",
+			args: []string{"-o", "custom-columns=NAME:.metadata.name,CLOUD:.metadata.labels.cloud,HUB:.spec.hubAcceptsClient"},
+		},
+		{
+			name: "custom-columns-no-headers",
+			args: []string{"-o", "custom-columns=NAME:.metadata.name,CLOUD:.metadata.labels.cloud", "--no-headers"},
+		},
+		{name: "sort-by", args: []string{"--sort-by=.metadata.name"}},
+		{name: "sort-by-no-server-print", args: []string{"--sort-by=.metadata.name", "--server-print=false"}},
+		{name: "sort-by-policies-all-namespaces", args: []string{"policies", "-A", "--sort-by=.metadata.name"}},
+		{name: "sort-by-json", args: []string{"--sort-by=.metadata.name", "-o", "json"}},
+		{name: "sort-by-name", args: []string{"--sort-by=.metadata.name", "-o", "name"}},
+		{name: "no-headers", args: []string{"--no-headers"}},
+		{name: "no-headers-policies-all-namespaces", args: []string{"policies", "-A", "--no-headers"}},
+		{name: "show-labels", args: []string{"--show-labels"}},
+		{name: "show-labels-policies-all-namespaces", args: []string{"policies", "-A", "--show-labels"}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			server := newEmptyServer(t)
+			if err := server.LoadFixtures(os.DirFS(testdataDir), fixturesDir); err != nil {
+				t.Fatalf("unable to load fixtures: %v", err)
+			}
+
+			out, errOut := run(t, append([]string{"get"}, test.args...)...)
+			if errOut != "" {
+				t.Fatalf("unexpected error output: %s", errOut)
+			}
+
+			expectGolden(t, filepath.Join(testdataDir, goldenDir, test.name+".golden"), out)
+		})
+	}
+}
+
+// expectGolden compares the output with the golden file, or writes the golden file if -update is specified
+func expectGolden(t *testing.T, goldenFile, output string) {
+	t.Helper()
+
+	if *update {
+		if err := ioutil.WriteFile(goldenFile, []byte(output), 0o600); err != nil {
+			t.Fatalf("unable to update golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("unable to read golden file, run the test with -update to create it: %v", err)
+	}
+
+	if !bytes.Equal(expected, []byte(output)) {
+		t.Errorf("output differs from %s, run the test with -update if expected\nexpected:\n%s\ngot:\n%s",
+			goldenFile, expected, output)
+	}
+}
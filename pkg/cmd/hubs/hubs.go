@@ -0,0 +1,293 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	kubectlget "k8s.io/kubectl/pkg/cmd/get"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+var (
+	hubsLong = templates.LongDesc(i18n.T(`
+		Display the leaf hubs of the hub of hubs.
+
+		For each leaf hub, prints the number of its managed clusters by their availability,
+		the last time the status of its managed clusters was synced and whether the leaf hub
+		looks stale, i.e. its status was not synced for longer than --stale-after.
+		Managed clusters with no leaf hub are shown under <none>.`))
+
+	hubsExample = templates.Examples(i18n.T(`
+		# List all leaf hubs
+		%[1]s hubs
+
+		# List all leaf hubs, considering a leaf hub stale if its status was not synced for 30 minutes
+		%[1]s hubs --stale-after 30m
+
+		# List all leaf hubs in YAML output format
+		%[1]s hubs -o yaml`))
+
+	// LeafHubGroupVersionKind is the kind of the leaf hubs printed by the hubs command
+	LeafHubGroupVersionKind = schema.GroupVersionKind{
+		Group:   "hub-of-hubs.open-cluster-management.io",
+		Version: "v1",
+		Kind:    "LeafHub",
+	}
+)
+
+const (
+	defaultStaleAfter = 10 * time.Minute
+	noLeafHub         = pluginutil.NoneValue
+)
+
+// Options contains the input to the hubs command.
+type Options struct {
+	PrintFlags *kubectlget.PrintFlags
+
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	StaleAfter time.Duration
+
+	client        *client.Client
+	humanReadable bool
+}
+
+// leafHub holds the status of a leaf hub aggregated from the status of its managed clusters
+type leafHub struct {
+	name           string
+	clusters       int
+	available      int
+	unavailable    int
+	unknown        int
+	lastStatusSync time.Time
+}
+
+// NewOptions returns an Options for the hubs command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		PrintFlags: kubectlget.NewGetPrintFlags(),
+		StaleAfter: defaultStaleAfter,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "hubs" action, which lists the leaf hubs.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use: fmt.Sprintf("hubs [(-o|--output=)%s]",
+			strings.Join(o.PrintFlags.AllowedFormats(), "|")),
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Display the leaf hubs with their health and managed cluster counts"),
+		Long:                  hubsLong,
+		Example:               fmt.Sprintf(hubsExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	cmd.Flags().DurationVar(&o.StaleAfter, "stale-after", o.StaleAfter,
+		"The duration since the last status sync after which a leaf hub is considered stale.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	outputFormat := cmdutil.GetFlagString(cmd, "output")
+	o.humanReadable = outputFormat == "" || outputFormat == "wide"
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run performs the hubs operation.
+func (o *Options) Run() error {
+	managedClusters, err := o.client.ListManagedClusters(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	leafHubs := aggregateLeafHubs(managedClusters)
+
+	printer, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	if sortBy := *o.PrintFlags.HumanReadableFlags.SortBy; sortBy != "" {
+		printer = &kubectlget.SortingPrinter{Delegate: printer, SortField: sortBy}
+	}
+
+	if o.humanReadable {
+		w := printers.GetNewTabWriter(o.Out)
+		defer w.Flush()
+
+		return printer.PrintObj(o.toTable(leafHubs), w)
+	}
+
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"kind":       "List",
+			"apiVersion": "v1",
+			"metadata":   map[string]interface{}{},
+		},
+	}
+	for _, hub := range leafHubs {
+		list.Items = append(list.Items, *o.toObject(hub))
+	}
+
+	if len(list.Items) == 1 {
+		return printer.PrintObj(&list.Items[0], o.Out)
+	}
+
+	return printer.PrintObj(list, o.Out)
+}
+
+func aggregateLeafHubs(managedClusters []*clusterv1.ManagedCluster) []*leafHub {
+	leafHubsByName := map[string]*leafHub{}
+
+	for _, managedCluster := range managedClusters {
+		name := pluginutil.GetLeafHubName(managedCluster)
+		if name == "" {
+			name = noLeafHub
+		}
+
+		hub, found := leafHubsByName[name]
+		if !found {
+			hub = &leafHub{name: name}
+			leafHubsByName[name] = hub
+		}
+
+		hub.clusters++
+
+		switch pluginutil.GetConditionStatus(managedCluster, clusterv1.ManagedClusterConditionAvailable) {
+		case metav1.ConditionTrue:
+			hub.available++
+		case metav1.ConditionFalse:
+			hub.unavailable++
+		default:
+			hub.unknown++
+		}
+
+		if lastStatusSync, found := pluginutil.GetLastStatusSync(managedCluster); found &&
+			lastStatusSync.After(hub.lastStatusSync) {
+			hub.lastStatusSync = lastStatusSync
+		}
+	}
+
+	leafHubs := make([]*leafHub, 0, len(leafHubsByName))
+	for _, hub := range leafHubsByName {
+		leafHubs = append(leafHubs, hub)
+	}
+
+	sort.Slice(leafHubs, func(i, j int) bool {
+		return leafHubs[i].name < leafHubs[j].name
+	})
+
+	return leafHubs
+}
+
+// stale returns True if the status of the leaf hub was not synced in the stale duration,
+// Unknown if the last status sync of the leaf hub is unknown
+func (o *Options) stale(hub *leafHub) metav1.ConditionStatus {
+	if hub.lastStatusSync.IsZero() {
+		return metav1.ConditionUnknown
+	}
+
+	if time.Since(hub.lastStatusSync) > o.StaleAfter {
+		return metav1.ConditionTrue
+	}
+
+	return metav1.ConditionFalse
+}
+
+func (o *Options) toObject(hub *leafHub) *unstructured.Unstructured {
+	status := map[string]interface{}{
+		"managedClusters":            int64(hub.clusters),
+		"availableManagedClusters":   int64(hub.available),
+		"unavailableManagedClusters": int64(hub.unavailable),
+		"unknownManagedClusters":     int64(hub.unknown),
+		"stale":                      string(o.stale(hub)),
+	}
+	if !hub.lastStatusSync.IsZero() {
+		status["lastStatusSync"] = hub.lastStatusSync.UTC().Format(time.RFC3339)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+	obj.SetGroupVersionKind(LeafHubGroupVersionKind)
+	obj.SetName(hub.name)
+
+	return obj
+}
+
+func (o *Options) toTable(leafHubs []*leafHub) *metav1.Table {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Clusters", Type: "integer"},
+			{Name: "Available", Type: "integer"},
+			{Name: "Unavailable", Type: "integer"},
+			{Name: "Unknown", Type: "integer"},
+			{Name: "Last Status Sync", Type: "string"},
+			{Name: "Stale", Type: "string"},
+		},
+	}
+	table.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("Table"))
+
+	for _, hub := range leafHubs {
+		lastStatusSync := pluginutil.UnknownValue
+		if !hub.lastStatusSync.IsZero() {
+			lastStatusSync = duration.HumanDuration(time.Since(hub.lastStatusSync)) + " ago"
+		}
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{
+				hub.name, hub.clusters, hub.available, hub.unavailable, hub.unknown, lastStatusSync,
+				string(o.stale(hub)),
+			},
+			Object: runtime.RawExtension{Object: o.toObject(hub)},
+		})
+	}
+
+	return table
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	kubeconfigLong = templates.LongDesc(i18n.T(`
+		Get a kubeconfig for a managed cluster through the hub of hubs.
+
+		The credentials are obtained from Non-K8s API, which gets or mints them on the leaf hub
+		of the managed cluster, e.g. by a managed service account or the cluster-proxy addon.
+		By default, the kubeconfig is printed. With --write, its cluster, user and context are
+		merged into the current kubeconfig file under the name of the managed cluster or
+		--context-name, and with --use its context becomes the current context. A cluster, user
+		or context of the kubeconfig file with the same name is only replaced with --overwrite.`))
+
+	kubeconfigExample = templates.Examples(i18n.T(`
+		# Print a kubeconfig for the managed cluster 'mycluster'
+		%[1]s kubeconfig mycluster
+
+		# Add a context for the managed cluster 'mycluster' to the current kubeconfig file and switch to it
+		%[1]s kubeconfig mycluster --write --use
+
+		# Add a context named 'prod-1' for the managed cluster 'mycluster' to the kubeconfig file 'clusters.yaml'
+		%[1]s kubeconfig mycluster --write --context-name prod-1 --kubeconfig clusters.yaml
+
+		# Replace the context for the managed cluster 'mycluster' with new credentials
+		%[1]s kubeconfig mycluster --write --overwrite`))
+
+	errNameConflict = errors.New("the kubeconfig file already has")
+)
+
+// Options contains the input to the kubeconfig command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	Write       bool
+	Use         bool
+	Overwrite   bool
+	ContextName string
+
+	client  *client.Client
+	cluster string
+}
+
+// NewOptions returns an Options for the kubeconfig command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "kubeconfig" action, which gets a kubeconfig for a managed cluster.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "kubeconfig CLUSTER [--write [--context-name NAME] [--use] [--overwrite]]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Get a kubeconfig for a managed cluster"),
+		Long:                  kubeconfigLong,
+		Example:               fmt.Sprintf(kubeconfigExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Write, "write", o.Write, "If true, merge the kubeconfig into the current kubeconfig file instead of printing it.")
+	cmd.Flags().BoolVar(&o.Use, "use", o.Use, "If true, set the written context as the current context. Requires --write.")
+	cmd.Flags().BoolVar(&o.Overwrite, "overwrite", o.Overwrite, "If true, replace the cluster, user and context of the kubeconfig file with the same name. Requires --write.")
+	cmd.Flags().StringVar(&o.ContextName, "context-name", o.ContextName, "The name of the cluster, user and context of the kubeconfig. Defaults to the name of the managed cluster.")
+
+	cmd.ValidArgsFunction = completion.ManagedClusterNames(clientFactory, false)
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one CLUSTER must be specified, got: %v", args)
+	}
+
+	o.cluster = args[0]
+	if o.ContextName == "" {
+		o.ContextName = o.cluster
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if o.Use && !o.Write {
+		return cmdutil.UsageErrorf(cmd, "--use can only be used with --write")
+	}
+	if o.Overwrite && !o.Write {
+		return cmdutil.UsageErrorf(cmd, "--overwrite can only be used with --write")
+	}
+
+	return nil
+}
+
+// Run performs the kubeconfig operation.
+func (o *Options) Run() error {
+	config, err := o.client.GetManagedClusterKubeconfig(context.TODO(), o.cluster)
+	if err != nil {
+		return err
+	}
+
+	config, err = pluginutil.RenameKubeconfig(config, o.ContextName)
+	if err != nil {
+		return err
+	}
+
+	if !o.Write {
+		data, err := clientcmd.Write(*config)
+		if err != nil {
+			return fmt.Errorf("unable to serialize kubeconfig: %w", err)
+		}
+
+		_, err = o.Out.Write(data)
+
+		return err
+	}
+
+	return o.write(config)
+}
+
+// write merges the cluster, user and context of the config into the current kubeconfig file. It fails if the
+// kubeconfig file has a cluster, user or context with the same name, unless --overwrite is specified.
+func (o *Options) write(config *clientcmdapi.Config) error {
+	configAccess := o.configFlags.ToRawKubeConfigLoader().ConfigAccess()
+
+	startingConfig, err := configAccess.GetStartingConfig()
+	if err != nil {
+		return err
+	}
+
+	if conflicts := nameConflicts(startingConfig, config); len(conflicts) > 0 && !o.Overwrite {
+		return fmt.Errorf("%w %s: %s, use --overwrite to replace them", errNameConflict,
+			configAccess.GetDefaultFilename(), strings.Join(conflicts, ", "))
+	}
+
+	for name, cluster := range config.Clusters {
+		startingConfig.Clusters[name] = cluster
+	}
+	for name, authInfo := range config.AuthInfos {
+		startingConfig.AuthInfos[name] = authInfo
+	}
+	for name, kubeContext := range config.Contexts {
+		startingConfig.Contexts[name] = kubeContext
+	}
+
+	if o.Use {
+		startingConfig.CurrentContext = o.ContextName
+	}
+
+	if err := clientcmd.ModifyConfig(configAccess, *startingConfig, true); err != nil {
+		return fmt.Errorf("unable to write kubeconfig: %w", err)
+	}
+
+	fmt.Fprintf(o.Out, "Context %q written to %s\n", o.ContextName, configAccess.GetDefaultFilename())
+	if o.Use {
+		fmt.Fprintf(o.Out, "Switched to context %q.\n", o.ContextName)
+	}
+
+	return nil
+}
+
+// nameConflicts returns the clusters, users and contexts of the config that the existing config already has
+func nameConflicts(existing, config *clientcmdapi.Config) []string {
+	var conflicts []string
+
+	for name := range config.Clusters {
+		if _, found := existing.Clusters[name]; found {
+			conflicts = append(conflicts, fmt.Sprintf("cluster %q", name))
+		}
+	}
+	for name := range config.AuthInfos {
+		if _, found := existing.AuthInfos[name]; found {
+			conflicts = append(conflicts, fmt.Sprintf("user %q", name))
+		}
+	}
+	for name := range config.Contexts {
+		if _, found := existing.Contexts[name]; found {
+			conflicts = append(conflicts, fmt.Sprintf("context %q", name))
+		}
+	}
+
+	sort.Strings(conflicts)
+
+	return conflicts
+}
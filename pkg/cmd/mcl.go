@@ -20,20 +20,106 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/apiresources"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/capacity"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/conditions"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/describe"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/diff"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/edit"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/events"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/exec"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/export"
 	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/get"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/hubs"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/kubeconfig"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/patch"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/summary"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/ui"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/views"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/wait"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/fake"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
-	clusterv1 "open-cluster-management.io/api/cluster/v1"
 )
 
 var managedClustersExample = `
 	# view managed clusters
 	%[1]s get
 
+	# view policies
+	%[1]s get policies
+
+	# describe a managed cluster
+	%[1]s describe managedcluster mycluster
+
+	# view the resources served by the hub of hubs
+	%[1]s api-resources
+
+	# view the health of managed clusters by leaf hub, using a built-in view
+	%[1]s get --view=health
+
+	# list the views of managed clusters
+	%[1]s views
+
+	# view placements in all namespaces with the managed clusters they selected
+	%[1]s get placements -A
+
+	# browse managed clusters in a terminal UI
+	%[1]s ui
+
+	# view leaf hubs
+	%[1]s hubs
+
+	# view a summary of managed clusters
+	%[1]s summary
+
+	# view the capacity of managed clusters
+	%[1]s capacity
+
+	# view the unhealthy conditions of managed clusters
+	%[1]s conditions --status False,Unknown
+
+	# view the warning events of a managed cluster
+	%[1]s events mycluster --types=Warning
+
+	# wait for a managed cluster to become available
+	%[1]s wait managedcluster mycluster --for=condition=ManagedClusterConditionAvailable
+
+	# add a context for a managed cluster to the current kubeconfig file
+	%[1]s kubeconfig mycluster --write
+
+	# run a command against all the production managed clusters
+	%[1]s exec -l environment=prod -- kubectl get nodes
+
+	# export the state of the fleet to a snapshot directory
+	%[1]s export -o fleet-snapshot
+
+	# compare a snapshot with the live state of the fleet
+	%[1]s diff fleet-snapshot
+
 	# label a managed cluster
 	%[1]s label mycluster environment=dev
+
+	# edit a managed cluster in the default editor
+	%[1]s edit managedcluster mycluster
+
+	# patch a managed cluster
+	%[1]s patch managedcluster mycluster -p '{"metadata":{"labels":{"environment":"dev"}}}'
+
+	# try the commands against a fake hub of hubs with a demo fleet
+	%[1]s get --fake
+
+	# load the completion of the commands, managed clusters and labels into the current bash shell
+	source <(%[1]s completion bash)
+
+	# print the managed clusters in the wide output format by default against the current context
+	%[1]s config set output wide
 `
 var defaultConfigFlags = genericclioptions.NewConfigFlags(true).WithDeprecatedPasswordFlag().WithDiscoveryBurst(300).WithDiscoveryQPS(50.0)
 
@@ -41,6 +127,14 @@ var defaultConfigFlags = genericclioptions.NewConfigFlags(true).WithDeprecatedPa
 type ManagedClustersOptions struct {
 	genericclioptions.IOStreams
 	configFlags *genericclioptions.ConfigFlags
+
+	Fake bool
+	// ClientConfig, if not nil, is the config of the clients of Non-K8s API instead of the kubeconfig, e.g. of a
+	// fake Non-K8s API
+	ClientConfig *client.Config
+
+	clientFactory *client.Factory
+	fakeServer    *fake.Server
 }
 
 // NewManagedClustersOptions provides an instance of ManagedClustersOptions with default values
@@ -51,15 +145,30 @@ func NewManagedClustersOptions(streams genericclioptions.IOStreams) *ManagedClus
 	}
 }
 
-type restScope struct{}
+type restScope struct {
+	name meta.RESTScopeName
+}
 
-func (restScope) Name() meta.RESTScopeName {
-	return meta.RESTScopeNameRoot
+func (s restScope) Name() meta.RESTScopeName {
+	return s.name
 }
 
+var (
+	rootScope      = restScope{name: meta.RESTScopeNameRoot}
+	namespaceScope = restScope{name: meta.RESTScopeNameNamespace}
+)
+
 // NewCmdManagedClusters provides a cobra command wrapping ManagedClustersOptions
 func NewCmdManagedClusters(streams genericclioptions.IOStreams) *cobra.Command {
-	o := NewManagedClustersOptions(streams)
+	return NewCmdManagedClustersWithOptions(NewManagedClustersOptions(streams))
+}
+
+// NewCmdManagedClustersWithOptions provides a cobra command wrapping the ManagedClustersOptions, e.g. with a
+// ClientConfig of a fake Non-K8s API
+func NewCmdManagedClustersWithOptions(o *ManagedClustersOptions) *cobra.Command {
+	o.clientFactory = client.NewFactory(o.configFlags)
+	o.clientFactory.SetConfig(o.ClientConfig)
+
 	cmd := &cobra.Command{
 		Use: "kubectl-mc",
 		Short: "Operate managed clusters for Hub of Hubs\n\n" +
@@ -67,23 +176,26 @@ func NewCmdManagedClusters(streams genericclioptions.IOStreams) *cobra.Command {
 		DisableFlagsInUseLine: true,
 		Example:               fmt.Sprintf(managedClustersExample, "kubectl-mc"),
 		Run:                   runHelp,
-	}
-
-	cmd.CompletionOptions.DisableDefaultCmd = true
-
-	mapping := &meta.RESTMapping{
-		Resource: schema.GroupVersionResource{
-			Group:    clusterv1.GroupName,
-			Version:  clusterv1.GroupVersion.Version,
-			Resource: "managedclusters",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.startFake(); err != nil {
+				return err
+			}
+			if !appliesSettings(cmd) {
+				return nil
+			}
+			return o.applySettings(cmd)
 		},
-		GroupVersionKind: schema.GroupVersionKind{
-			Group:   clusterv1.GroupName,
-			Version: clusterv1.GroupVersion.Version,
-			Kind:    "ManagedCluster",
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			o.stopFake()
 		},
-		Scope: restScope{},
 	}
+
+	// the commands use the resources discovered from Non-K8s API, or else the built-in resources
+	builtinRegistry, resourceRegistry := newRegistry(), newRegistry()
+	resourceRegistry.SetDiscovery(func() (*registry.Registry, error) {
+		return apiresources.Discover(o.clientFactory, builtinRegistry, discoveryPath, false, o.ErrOut)
+	})
+
 	flags := cmd.PersistentFlags()
 
 	kubeConfigFlags := o.configFlags
@@ -97,12 +209,106 @@ func NewCmdManagedClusters(streams genericclioptions.IOStreams) *cobra.Command {
 	f := cmdutil.NewFactory(matchVersionKubeConfigFlags)
 
 	o.configFlags.AddFlags(flags)
-	cmd.AddCommand(get.NewCmd("kubectl-mc", f, o.configFlags, o.IOStreams, mapping,
-		"managedclusters", "managed clusters"))
+	flags.BoolVar(&o.Fake, "fake", o.Fake, "If true, run against an in-process fake Non-K8s API serving a demo fleet, instead of the hub of hubs.")
+
+	cmd.AddCommand(get.NewCmd("kubectl-mc", f, o.clientFactory, o.IOStreams, resourceRegistry))
+	cmd.AddCommand(describe.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, resourceRegistry, eventsPath))
+	cmd.AddCommand(apiresources.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, builtinRegistry, discoveryPath))
+	cmd.AddCommand(edit.NewCmd(o.clientFactory, o.IOStreams, resourceRegistry))
+	cmd.AddCommand(patch.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, resourceRegistry))
+	cmd.AddCommand(hubs.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(summary.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(capacity.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(conditions.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(events.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, eventsPath))
+	cmd.AddCommand(wait.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, resourceRegistry))
+	cmd.AddCommand(kubeconfig.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(exec.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(export.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, resourcePaths(builtinRegistry)))
+	cmd.AddCommand(diff.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams))
+	cmd.AddCommand(completion.NewCmd("kubectl-mc", o.IOStreams))
+	cmd.AddCommand(config.NewCmd("kubectl-mc", o.configFlags, o.IOStreams))
+	cmd.AddCommand(views.NewCmd("kubectl-mc", o.IOStreams))
+	cmd.AddCommand(ui.NewCmd("kubectl-mc", o.clientFactory, o.IOStreams, eventsPath))
 
 	return cmd
 }
 
+// startFake starts the fake Non-K8s API with the default fixtures if --fake is specified, and makes the commands
+// use it
+func (o *ManagedClustersOptions) startFake() error {
+	if !o.Fake {
+		return nil
+	}
+
+	o.fakeServer = fake.NewServer()
+	if err := o.fakeServer.LoadDefaultFixtures(); err != nil {
+		o.fakeServer.Close()
+		return fmt.Errorf("unable to load the fake fleet: %w", err)
+	}
+
+	o.clientFactory.SetConfig(o.fakeServer.Config())
+
+	return nil
+}
+
+// stopFake stops the fake Non-K8s API if it was started
+func (o *ManagedClustersOptions) stopFake() {
+	if o.fakeServer == nil {
+		return
+	}
+
+	o.clientFactory.SetConfig(o.ClientConfig)
+	o.fakeServer.Close()
+	o.fakeServer = nil
+}
+
+// applySettings sets the flags of the command that are not specified to the values of the view of the --view
+// flag, if any, and then to the defaults of the settings of the current context in the config file, before the
+// command completes its options
+func (o *ManagedClustersOptions) applySettings(cmd *cobra.Command) error {
+	config, err := pluginconfig.Load(pluginconfig.Path())
+	if err != nil {
+		return err
+	}
+
+	if err := config.ApplyView(cmd.Flags()); err != nil {
+		return err
+	}
+
+	contextName, err := pluginconfig.CurrentContext(o.configFlags)
+	if err != nil {
+		return err
+	}
+
+	return config.SettingsFor(contextName).ApplyFlagDefaults(cmd.Flags())
+}
+
+// appliesSettings returns false for the commands that manage the config file, or that don't run against Non-K8s
+// API, so that they keep working with a malformed config file
+func appliesSettings(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		switch cmd.Name() {
+		case "config", "views", "completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
+	}
+
+	return true
+}
+
+// resourcePaths returns the paths in Non-K8s API of the registered resources that can be listed
+func resourcePaths(resourceRegistry *registry.Registry) []string {
+	paths := make([]string, 0, len(resourceRegistry.Resources()))
+	for _, resource := range resourceRegistry.Resources() {
+		if resource.Allows(registry.VerbList) {
+			paths = append(paths, resource.Path)
+		}
+	}
+
+	return paths
+}
+
 func runHelp(cmd *cobra.Command, args []string) {
 	//nolint:errcheck
 	cmd.Help()
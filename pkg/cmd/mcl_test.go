@@ -0,0 +1,937 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package cmd_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/fake"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// fatalError is raised by cmdutil.CheckErr in the tests instead of exiting
+type fatalError struct {
+	message string
+}
+
+// clientConfig is the config of the clients of the commands run by run, of the fake Non-K8s API of the test
+var clientConfig *client.Config
+
+// newServer starts a fake Non-K8s API with the default fixtures and makes the commands use it
+func newServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	server := newEmptyServer(t)
+	if err := server.LoadDefaultFixtures(); err != nil {
+		t.Fatalf("unable to load fixtures: %v", err)
+	}
+
+	return server
+}
+
+// newEmptyServer starts a fake Non-K8s API without objects and makes the commands use it
+func newEmptyServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	// the commands must not depend on the kubeconfig and the config file of the user
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "kubeconfig"))
+	t.Setenv(pluginconfig.EnvVar, filepath.Join(t.TempDir(), "config.yaml"))
+
+	server := fake.NewServer()
+	clientConfig = server.Config()
+
+	t.Cleanup(func() {
+		clientConfig = nil
+		server.Close()
+	})
+
+	return server
+}
+
+// run runs the kubectl-mc command with the arguments, and returns its output and its error output. The error
+// output includes the fatal error of the command, if any.
+func run(t *testing.T, args ...string) (string, string) {
+	t.Helper()
+
+	streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+
+	cmdutil.BehaviorOnFatal(func(message string, code int) {
+		panic(fatalError{message: message})
+	})
+	defer cmdutil.DefaultBehaviorOnFatal()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fatal, ok := r.(fatalError)
+				if !ok {
+					panic(r)
+				}
+				errOut.WriteString(fatal.message)
+			}
+		}()
+
+		o := cmd.NewManagedClustersOptions(streams)
+		o.ClientConfig = clientConfig
+
+		root := cmd.NewCmdManagedClustersWithOptions(o)
+		root.SetArgs(args)
+		root.SetOut(out)
+		root.SetErr(errOut)
+
+		if err := root.Execute(); err != nil {
+			errOut.WriteString(err.Error())
+		}
+	}()
+
+	return out.String(), errOut.String()
+}
+
+func expectContains(t *testing.T, output string, expected ...string) {
+	t.Helper()
+
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("expected %q in output:\n%s", s, output)
+		}
+	}
+}
+
+func TestGetManagedClusters(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "get")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "NAME", "HUB ACCEPTED", "AVAILABLE", "cluster1", "cluster2", "cluster3", "cluster4")
+}
+
+func TestGetManagedClusterByName(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "get", "managedcluster", "cluster1", "--no-headers")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 1 || !strings.HasPrefix(lines[0], "cluster1 ") {
+		t.Errorf("unexpected output of the managed cluster: %q", out)
+	}
+
+	// a single object is printed as is, not as a list
+	out, _ = run(t, "get", "-o", "json", "managedcluster", "cluster1")
+	expectContains(t, out, `"kind": "ManagedCluster"`, `"name": "cluster1"`)
+
+	_, errOut = run(t, "get", "managedcluster", "cluster1", "cluster5")
+	expectContains(t, errOut, "not found", "cluster5")
+
+	out, errOut = run(t, "get", "managedcluster", "cluster5", "--ignore-not-found")
+	if out != "" || errOut != "" {
+		t.Errorf("unexpected output: %q, error output: %q", out, errOut)
+	}
+}
+
+func TestGetPagedManagedClusters(t *testing.T) {
+	server := newServer(t)
+	server.SetPageSize(1)
+
+	out, errOut := run(t, "get", "mcl", "-o", "name")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "managedcluster.cluster.open-cluster-management.io/cluster1",
+		"managedcluster.cluster.open-cluster-management.io/cluster4")
+}
+
+func TestGetPagedTable(t *testing.T) {
+	server := newServer(t)
+	server.SetPageSize(3)
+
+	out, errOut := run(t, "get", "--no-headers")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 4 {
+		t.Errorf("expected the rows of the 2 pages of the table:\n%s", out)
+	}
+
+	continued := false
+	for _, request := range server.Requests() {
+		continued = continued || request.Query.Get("continue") == "3"
+	}
+
+	if !continued {
+		t.Error("expected a request of the second page")
+	}
+}
+
+func TestGetPoliciesInAllNamespaces(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "get", "plc", "-A")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "NAMESPACE", "COMPLIANCE STATE", "default", "policy-pod", "NonCompliant")
+}
+
+func TestGetGroupBy(t *testing.T) {
+	server := newServer(t)
+
+	out, errOut := run(t, "get", "--group-by", "{.status.version.kubernetes}")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	// the version is read from the objects of the rows of the table, included only if requested
+	expectContains(t, out, "{.status.version.kubernetes}: v1.23.5 (3)", "cluster1")
+
+	requests := server.Requests()
+	if query := requests[len(requests)-1].Query; query.Get("includeObject") != "Object" {
+		t.Errorf("expected the objects of the rows requested, got query %v", query)
+	}
+}
+
+func TestGetPlacements(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "get", "placements", "-n", "apps", "--no-headers")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	if fields := strings.Fields(out); len(fields) != 4 || fields[0] != "placement-prod" || fields[1] != "2" ||
+		fields[2] != "cluster2,cluster3" {
+		t.Errorf("unexpected output of the placements: %q", out)
+	}
+
+	// the selected clusters are gathered from all the placement decisions of a placement
+	out, _ = run(t, "get", "placements", "-A")
+	expectContains(t, out, "SELECTED CLUSTERS", "placement-all    4          cluster1,cluster2,cluster3,cluster4")
+
+	out, _ = run(t, "get", "placementrules", "-A")
+	expectContains(t, out, "SELECTED CLUSTERS", "placement-pod", "cluster1,cluster3")
+}
+
+func TestGetFailure(t *testing.T) {
+	server := newServer(t)
+	server.InjectError(fake.Error{
+		Path:       client.ManagedClustersPath,
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "leaf hubs unavailable",
+	})
+
+	_, errOut := run(t, "get")
+
+	expectContains(t, errOut, "503", "leaf hubs unavailable")
+}
+
+func TestGetIgnoreNotFound(t *testing.T) {
+	server := newServer(t)
+	server.InjectError(fake.Error{Path: client.ManagedClustersPath, StatusCode: http.StatusNotFound})
+
+	out, errOut := run(t, "get", "--ignore-not-found")
+	if out != "" || errOut != "" {
+		t.Errorf("unexpected output: %q, error output: %q", out, errOut)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "describe", "managedcluster", "cluster1")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "Name:", "cluster1", "environment=dev", "Events:", "Normal")
+}
+
+func TestHubsAndSummary(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "hubs")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "hub1", "hub2")
+
+	out, errOut = run(t, "summary")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "Managed Clusters:", "True=2  False=1  Unknown=1")
+}
+
+func TestCapacity(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "capacity", "--sort-by", "cpu", "--no-headers")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[3], "cluster2") || !strings.HasPrefix(lines[4], "TOTAL") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	expectContains(t, lines[4], "4 clusters", "56", "224.0Gi")
+}
+
+func TestConditions(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "conditions", "--status", "Unknown")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "cluster2   ManagedClusterConditionAvailable   Unknown")
+	if strings.Contains(out, "cluster1") {
+		t.Errorf("unexpected cluster1 in output:\n%s", out)
+	}
+
+	_, errOut = run(t, "conditions", "--status", "Maybe")
+	expectContains(t, errOut, "Maybe")
+}
+
+func TestEvents(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "events", "cluster2")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "LeaseExpired")
+}
+
+func TestPatch(t *testing.T) {
+	server := newServer(t)
+
+	out, errOut := run(t, "patch", "mcl", "cluster1", "-p", `{"metadata":{"labels":{"environment":"prod"}}}`)
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "cluster1 patched")
+
+	if got := server.Objects(client.ManagedClustersPath)[0].GetLabels()["environment"]; got != "prod" {
+		t.Errorf("unexpected label: %q", got)
+	}
+
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; last.Method != http.MethodPatch ||
+		last.ContentType != string(types.MergePatchType) {
+		t.Errorf("unexpected request: %s %s", last.Method, last.ContentType)
+	}
+
+	// the patch of a namespaced resource is sent to the path of its namespace
+	out, errOut = run(t, "patch", "policy", "policy-pod", "-n", "default", "-p", `{"spec":{"disabled":true}}`)
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "policy-pod patched")
+
+	requests = server.Requests()
+	if last := requests[len(requests)-1]; last.Path != "policies/namespaces/default/policy-pod" {
+		t.Errorf("unexpected path: %s", last.Path)
+	}
+
+	if disabled, _, _ := unstructured.NestedBool(server.Objects("policies")[0].Object, "spec", "disabled"); !disabled {
+		t.Errorf("the policy was not disabled")
+	}
+
+	_, errOut = run(t, "patch", "policy", "policy-pod", "-n", "apps", "-p", `{"spec":{"disabled":true}}`)
+	expectContains(t, errOut, "not found")
+}
+
+func TestEdit(t *testing.T) {
+	server := newServer(t)
+
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\nsed -i 's/environment: dev/environment: test/' \"$1\"\n"),
+		0o700); err != nil {
+		t.Fatalf("unable to write the editor: %v", err)
+	}
+	t.Setenv("KUBE_EDITOR", editor)
+
+	out, errOut := run(t, "edit", "mcl", "cluster1")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "cluster1 edited")
+
+	if got := server.Objects(client.ManagedClustersPath)[0].GetLabels()["environment"]; got != "test" {
+		t.Errorf("unexpected label: %q", got)
+	}
+
+	// a policy with the same name in another namespace is not edited
+	policy := server.Objects("policies")[0]
+	policy.SetNamespace("apps")
+	policy.SetLabels(map[string]string{"environment": "dev"})
+	server.Apply("policies", policy)
+
+	out, errOut = run(t, "edit", "policy", policy.GetName(), "-n", "apps")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, policy.GetName()+" edited")
+
+	expected := map[string]string{"default": "", "apps": "test"}
+	for _, obj := range server.Objects("policies") {
+		if got := obj.GetLabels()["environment"]; got != expected[obj.GetNamespace()] {
+			t.Errorf("unexpected label of the policy in %s: %q", obj.GetNamespace(), got)
+		}
+	}
+
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; last.Path != "policies/namespaces/apps/"+policy.GetName() {
+		t.Errorf("unexpected path: %s", last.Path)
+	}
+}
+
+func TestWait(t *testing.T) {
+	server := newServer(t)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+
+		managedCluster := server.Objects(client.ManagedClustersPath)[1]
+		managedCluster.Object["status"].(map[string]interface{})["conditions"] = []interface{}{
+			map[string]interface{}{"type": "ManagedClusterConditionAvailable", "status": "True"},
+		}
+		server.Apply(client.ManagedClustersPath, managedCluster)
+	}()
+
+	out, errOut := run(t, "wait", "managedclusters", "cluster2", "--for=condition=ManagedClusterConditionAvailable", "--timeout=10s")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "cluster2 condition met")
+}
+
+func TestWaitWatchForbidden(t *testing.T) {
+	server := newServer(t)
+	server.InjectError(fake.Error{
+		Path:       client.ManagedClustersPath,
+		Watch:      true,
+		StatusCode: http.StatusForbidden,
+		Message:    "watching managed clusters is forbidden",
+	})
+
+	// the forbidden watch fails the wait immediately instead of being retried until the timeout
+	start := time.Now()
+	_, errOut := run(t, "wait", "managedclusters", "cluster2", "--for=condition=ManagedClusterConditionAvailable",
+		"--timeout=10s")
+
+	expectContains(t, errOut, "403", "watching managed clusters is forbidden")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the wait to fail immediately, failed after %v", elapsed)
+	}
+}
+
+func TestKubeconfig(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "kubeconfig", "cluster1")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "https://api.cluster1.example.com:6443", "current-context: cluster1")
+}
+
+func TestKubeconfigWrite(t *testing.T) {
+	newServer(t)
+
+	hub := clientcmdapi.NewConfig()
+	hub.Clusters["hub"] = &clientcmdapi.Cluster{Server: "https://api.hub.example.com:6443"}
+	hub.AuthInfos["admin"] = &clientcmdapi.AuthInfo{Token: "hub-token"}
+	hub.Contexts["hub"] = &clientcmdapi.Context{Cluster: "hub", AuthInfo: "admin"}
+	hub.CurrentContext = "hub"
+
+	kubeconfigPath := os.Getenv("KUBECONFIG")
+	if err := clientcmd.WriteToFile(*hub, kubeconfigPath); err != nil {
+		t.Fatalf("unable to write the kubeconfig: %v", err)
+	}
+
+	// the context of the hub is not replaced by the context of the managed cluster
+	_, errOut := run(t, "kubeconfig", "cluster1", "--write", "--context-name", "hub")
+	expectContains(t, errOut, "the kubeconfig file already has", `cluster "hub", context "hub"`, "use --overwrite")
+
+	written, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("unable to read the kubeconfig: %v", err)
+	}
+
+	if written.Clusters["hub"].Server != "https://api.hub.example.com:6443" {
+		t.Errorf("expected the cluster of the hub kept, got %s", written.Clusters["hub"].Server)
+	}
+
+	out, errOut := run(t, "kubeconfig", "cluster1", "--write", "--context-name", "hub", "--overwrite")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, `Context "hub" written`)
+
+	if written, err = clientcmd.LoadFromFile(kubeconfigPath); err != nil {
+		t.Fatalf("unable to read the kubeconfig: %v", err)
+	}
+
+	if written.Clusters["hub"].Server != "https://api.cluster1.example.com:6443" {
+		t.Errorf("expected the cluster of the hub replaced, got %s", written.Clusters["hub"].Server)
+	}
+
+	out, errOut = run(t, "kubeconfig", "cluster2", "--write")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, `Context "cluster2" written`)
+}
+
+func TestExec(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "exec", "-l", "environment=prod", "--hub", "hub1", "--", "sh", "-c",
+		`echo "$MCL_CLUSTER" && test -f "$KUBECONFIG"`)
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "[cluster2] cluster2", "cluster2   0")
+
+	_, errOut = run(t, "exec", "cluster1", "cluster3", "--", "sh", "-c", `test "$MCL_CLUSTER" = cluster1`)
+	expectContains(t, errOut, "the command failed on 1 of 2 managed clusters")
+
+	_, errOut = run(t, "exec", "cluster1", "--hub", "hub1", "--", "true")
+	expectContains(t, errOut, "NAME cannot be combined with -l, --all or --hub")
+}
+
+func TestExportPaged(t *testing.T) {
+	server := newServer(t)
+	server.SetPageSize(3)
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot")
+	if _, errOut := run(t, "export", "-o", snapshot); errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	// the snapshot has the objects of every page
+	out, errOut := run(t, "get", "--from-snapshot", snapshot, "-o", "name")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 4 {
+		t.Errorf("unexpected managed clusters of the snapshot:\n%s", out)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	newServer(t)
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot")
+	if _, errOut := run(t, "export", "-o", snapshot); errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	out, errOut := run(t, "diff", snapshot)
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "No differences found")
+
+	if _, errOut = run(t, "patch", "mcl", "cluster1", "-p", `{"metadata":{"labels":{"environment":"prod"}}}`); errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	out, errOut = run(t, "diff", snapshot)
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "Changed managed clusters (1):", "~ cluster1", "environment: dev -> prod")
+}
+
+func TestAPIResources(t *testing.T) {
+	newServer(t)
+
+	out, errOut := run(t, "api-resources", "--cache-dir", t.TempDir())
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "managedclustersets", "mclset")
+}
+
+func TestGetDiscoveredResources(t *testing.T) {
+	newServer(t)
+
+	// managed cluster sets are not built into the plugin, only discovered from Non-K8s API
+	out, errOut := run(t, "get", "mclset", "-o", "name")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "managedclusterset.cluster.open-cluster-management.io/global")
+}
+
+func TestFake(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "kubeconfig"))
+	t.Setenv(pluginconfig.EnvVar, filepath.Join(t.TempDir(), "config.yaml"))
+
+	out, errOut := run(t, "get", "--fake")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "cluster1", "cluster4")
+
+	// the fake is stopped after the command, so the kubeconfig is used again
+	_, errOut = run(t, "get")
+	if errOut == "" {
+		t.Errorf("expected an error without the fake and a kubeconfig")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	newServer(t)
+
+	for _, args := range [][]string{
+		{"config", "set", "output", "name"},
+		{"config", "set", "selector", "environment=prod"},
+		{"config", "set", "retries", "3"},
+	} {
+		out, errOut := run(t, args...)
+		if errOut != "" {
+			t.Fatalf("unexpected error output: %s", errOut)
+		}
+
+		expectContains(t, out, "set for context")
+	}
+
+	out, _ := run(t, "config", "view")
+	expectContains(t, out, "output: name", "selector: environment=prod", "retries: 3")
+
+	// the settings are defaults of the flags
+	out, errOut := run(t, "get")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	if expected := "managedcluster.cluster.open-cluster-management.io/cluster2\n" +
+		"managedcluster.cluster.open-cluster-management.io/cluster3\n" +
+		"managedcluster.cluster.open-cluster-management.io/cluster4\n"; out != expected {
+		t.Errorf("unexpected output of the settings: %q", out)
+	}
+
+	out, _ = run(t, "get", "-o", "name", "-l", "")
+	expectContains(t, out, "cluster1", "cluster4")
+
+	// the selector setting only filters the managed clusters
+	out, _ = run(t, "get", "policies", "--all-namespaces")
+	expectContains(t, out, "policy-pod")
+
+	_, errOut = run(t, "config", "set", "retries", "many")
+	expectContains(t, errOut, "invalid value")
+
+	_, errOut = run(t, "config", "set", "color", "blue")
+	expectContains(t, errOut, "unknown setting")
+
+	run(t, "config", "unset", "output")
+	run(t, "config", "unset", "selector")
+
+	out, _ = run(t, "config", "view", "--minify")
+	if out != "contexts:\n  \"\":\n    retries: 3\n" {
+		t.Errorf("unexpected config: %q", out)
+	}
+
+	run(t, "config", "unset", "retries")
+
+	if out, _ = run(t, "config", "view"); out != "{}\n" {
+		t.Errorf("unexpected empty config: %q", out)
+	}
+}
+
+func TestMalformedConfig(t *testing.T) {
+	newServer(t)
+
+	if err := os.WriteFile(pluginconfig.Path(), []byte("contexts: ["), 0o600); err != nil {
+		t.Fatalf("unable to write the config file: %v", err)
+	}
+
+	_, errOut := run(t, "get")
+	expectContains(t, errOut, "unable to parse the config file")
+
+	// the commands that don't run against Non-K8s API don't apply the settings
+	out, errOut := run(t, "completion", "bash")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "bash completion")
+}
+
+func TestViews(t *testing.T) {
+	newServer(t)
+
+	out, _ := run(t, "views")
+	expectContains(t, out, "capacity", "health", "versions", "builtin")
+
+	out, errOut := run(t, "get", "--view", "health")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "hub: hub1 (2)", "hub: hub2 (2)", "AVAILABLE", "cluster4")
+
+	out, errOut = run(t, "views", "set", "upgrades", "-l", "environment=prod", "--sort-by", ".metadata.name",
+		"--columns", "NAME:.metadata.name,VERSION:.status.version.kubernetes", "--description", "Upgrades")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, `View "upgrades" saved.`)
+
+	out, _ = run(t, "views")
+	expectContains(t, out, "upgrades", "config", "Upgrades")
+
+	// the flags take precedence over the view
+	out, _ = run(t, "get", "--view", "upgrades", "--no-headers", "-l", "environment!=prod")
+	if out != "cluster1   v1.23.5\n" {
+		t.Errorf("unexpected output of the view: %q", out)
+	}
+
+	_, errOut = run(t, "get", "--view", "unknown")
+	expectContains(t, errOut, "unknown view")
+
+	_, errOut = run(t, "views", "delete", "health")
+	expectContains(t, errOut, "built-in views can not be deleted")
+
+	out, _ = run(t, "views", "delete", "upgrades")
+	expectContains(t, out, `View "upgrades" deleted.`)
+
+	if out, _ = run(t, "config", "view"); out != "{}\n" {
+		t.Errorf("unexpected empty config: %q", out)
+	}
+}
+
+func TestGetContexts(t *testing.T) {
+	server := newServer(t)
+
+	// the contexts use their settings of the config file instead of the fake
+	clientConfig = nil
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: fake.Token}
+
+	for _, contextName := range []string{"east", "west", "south"} {
+		kubeconfig.Clusters[contextName] = &clientcmdapi.Cluster{Server: "https://api." + contextName + ".example.com"}
+		kubeconfig.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName, AuthInfo: "user"}
+	}
+
+	kubeconfig.CurrentContext = "east"
+
+	if err := clientcmd.WriteToFile(*kubeconfig, os.Getenv("KUBECONFIG")); err != nil {
+		t.Fatalf("unable to write the kubeconfig: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, server.CertificatePEM(), 0o600); err != nil {
+		t.Fatalf("unable to write the CA file: %v", err)
+	}
+
+	config := &pluginconfig.Config{}
+	config.SetSettings("east", &pluginconfig.Settings{URL: server.URL(), CAFile: caFile})
+	config.SetSettings("west", &pluginconfig.Settings{URL: server.URL(), CAFile: caFile})
+	// nothing listens on the port 1, so the context fails
+	config.SetSettings("south", &pluginconfig.Settings{URL: "https://127.0.0.1:1", CAFile: caFile})
+
+	if err := config.Save(pluginconfig.Path()); err != nil {
+		t.Fatalf("unable to write the config file: %v", err)
+	}
+
+	out, errOut := run(t, "get", "--contexts", "west,east", "-l", "environment!=prod")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "CONTEXT   NAME") ||
+		!strings.HasPrefix(lines[1], "west      cluster1") || !strings.HasPrefix(lines[2], "east      cluster1") {
+		t.Errorf("unexpected output of the contexts:\n%s", out)
+	}
+
+	// the failure of a context is returned after the objects of the others are printed
+	out, errOut = run(t, "get", "--all-contexts", "-o", "name")
+	expectContains(t, errOut, `error: context "south"`)
+
+	if strings.Count(out, "managedcluster.cluster.open-cluster-management.io/cluster4\n") != 2 {
+		t.Errorf("expected cluster4 of the contexts east and west in output:\n%s", out)
+	}
+
+	out, _ = run(t, "get", "--contexts", "east", "-o", "yaml", "-l", "environment!=prod")
+	expectContains(t, out, "mcl.open-cluster-management.io/context: east")
+
+	_, errOut = run(t, "get", "--contexts", "east,north")
+	expectContains(t, errOut, "context not found: north")
+
+	_, errOut = run(t, "get", "--contexts", "south")
+	expectContains(t, errOut, `context "south"`)
+
+	_, errOut = run(t, "get", "--contexts", "east", "--all-contexts")
+	expectContains(t, errOut, "--contexts and --all-contexts cannot be used together")
+}
+
+func TestGetContextsWithDifferentColumns(t *testing.T) {
+	server := newServer(t)
+
+	// the contexts use their settings of the config file instead of the fake
+	clientConfig = nil
+
+	// another version of Non-K8s API, which serves tables with other columns
+	other := fake.NewServer()
+	t.Cleanup(other.Close)
+
+	other.SetRaw("policies", []byte(`{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+		"columnDefinitions":[{"name":"Name","type":"string","format":"name"}],
+		"rows":[{"cells":["policy-west"],"object":{"apiVersion":"policy.open-cluster-management.io/v1",
+		"kind":"Policy","metadata":{"name":"policy-west","namespace":"default"},
+		"spec":{"remediationAction":"enforce"}}}]}`))
+	other.SetRaw(client.ManagedClustersPath, []byte(`{"kind":"Table","apiVersion":"meta.k8s.io/v1",
+		"columnDefinitions":[{"name":"Name","type":"string","format":"name"},{"name":"Version","type":"string"}],
+		"rows":[{"cells":["cluster9","v2"],"object":{"apiVersion":"cluster.open-cluster-management.io/v1",
+		"kind":"ManagedCluster","metadata":{"name":"cluster9"}}}]}`))
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: fake.Token}
+
+	config := &pluginconfig.Config{}
+
+	for contextName, s := range map[string]*fake.Server{"east": server, "west": other} {
+		kubeconfig.Clusters[contextName] = &clientcmdapi.Cluster{Server: "https://api." + contextName + ".example.com"}
+		kubeconfig.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName, AuthInfo: "user"}
+
+		caFile := filepath.Join(t.TempDir(), "ca.crt")
+		if err := os.WriteFile(caFile, s.CertificatePEM(), 0o600); err != nil {
+			t.Fatalf("unable to write the CA file: %v", err)
+		}
+
+		config.SetSettings(contextName, &pluginconfig.Settings{URL: s.URL(), CAFile: caFile})
+	}
+
+	kubeconfig.CurrentContext = "east"
+
+	if err := clientcmd.WriteToFile(*kubeconfig, os.Getenv("KUBECONFIG")); err != nil {
+		t.Fatalf("unable to write the kubeconfig: %v", err)
+	}
+
+	if err := config.Save(pluginconfig.Path()); err != nil {
+		t.Fatalf("unable to write the config file: %v", err)
+	}
+
+	// the objects of the rows are converted to tables client-side
+	out, errOut := run(t, "get", "policies", "-A", "--contexts", "east,west")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "CONTEXT   NAMESPACE   NAME") ||
+		!strings.HasPrefix(lines[2], "west      default     policy-west   enforce") {
+		t.Errorf("unexpected output of the contexts:\n%s", out)
+	}
+
+	// without client-side columns, the tables with different columns are printed separately
+	out, errOut = run(t, "get", "--contexts", "east,west")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "CONTEXT   NAME       HUB ACCEPTED", "CONTEXT   NAME       VERSION", "west      cluster9   v2")
+}
+
+func TestTLS(t *testing.T) {
+	server := newServer(t)
+
+	// the client uses the settings of the config file instead of the fake
+	clientConfig = nil
+
+	writeKubeconfig := func(caData []byte) {
+		kubeconfig := clientcmdapi.NewConfig()
+		kubeconfig.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: fake.Token}
+		kubeconfig.Clusters["hub"] = &clientcmdapi.Cluster{
+			Server:                   "https://api.hub.example.com",
+			CertificateAuthorityData: caData,
+		}
+		kubeconfig.Contexts["hub"] = &clientcmdapi.Context{Cluster: "hub", AuthInfo: "user"}
+		kubeconfig.CurrentContext = "hub"
+
+		if err := clientcmd.WriteToFile(*kubeconfig, os.Getenv("KUBECONFIG")); err != nil {
+			t.Fatalf("unable to write the kubeconfig: %v", err)
+		}
+	}
+
+	config := &pluginconfig.Config{}
+	config.SetSettings("hub", &pluginconfig.Settings{URL: server.URL()})
+
+	if err := config.Save(pluginconfig.Path()); err != nil {
+		t.Fatalf("unable to write the config file: %v", err)
+	}
+
+	// the certificate of Non-K8s API is verified by the certificate authority of the hub of hubs
+	writeKubeconfig(server.CertificatePEM())
+
+	out, errOut := run(t, "get", "-o", "name")
+	if errOut != "" {
+		t.Fatalf("unexpected error output: %s", errOut)
+	}
+
+	expectContains(t, out, "managedcluster.cluster.open-cluster-management.io/cluster1")
+
+	writeKubeconfig(nil)
+
+	_, errOut = run(t, "get", "-o", "name")
+	expectContains(t, errOut, "certificate")
+
+	out, _ = run(t, "get", "-o", "name", "--insecure-skip-tls-verify")
+	expectContains(t, out, "managedcluster.cluster.open-cluster-management.io/cluster1")
+
+	run(t, "config", "set", "insecureSkipTLSVerify", "true")
+
+	out, _ = run(t, "get", "-o", "name")
+	expectContains(t, out, "managedcluster.cluster.open-cluster-management.io/cluster1")
+
+	_, errOut = run(t, "config", "set", "insecureSkipTLSVerify", "sometimes")
+	expectContains(t, errOut, "must be true or false")
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package patch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	patchLong = templates.LongDesc(i18n.T(`
+		Update fields of a resource using a JSON merge patch or a JSON patch.
+
+		The patch is sent to the Non-K8s API, so only the fields supported by the Non-K8s API
+		for the resource (e.g. labels of managed clusters) can be patched.
+
+		JSON and YAML formats are accepted.`))
+
+	patchExample = templates.Examples(i18n.T(`
+		# Add a label to a managed cluster using a merge patch
+		%[1]s patch managedcluster mycluster -p '{"metadata":{"labels":{"environment":"dev"}}}'
+
+		# Add a label to a managed cluster using a JSON patch
+		%[1]s patch managedcluster mycluster --type json -p '[{"op": "add", "path": "/metadata/labels/environment", "value": "dev"}]'
+
+		# Update a managed cluster using a merge patch in a YAML file
+		%[1]s patch managedcluster mycluster --patch-file patch.yaml
+
+		# Update a managed cluster and print the result in YAML format
+		%[1]s patch managedcluster mycluster -p '{"metadata":{"labels":{"environment":"dev"}}}' -o yaml
+
+		# Disable the policy 'mypolicy' in the namespace 'default'
+		%[1]s patch policy mypolicy -n default -p '{"spec":{"disabled":true}}'`))
+
+	errUnknownPatchType = errors.New("unknown patch type")
+)
+
+const (
+	mergePatchType = "merge"
+	jsonPatchType  = "json"
+)
+
+// patchTypes maps the values of --type to the types of the patch requests
+var patchTypes = map[string]types.PatchType{
+	mergePatchType: types.MergePatchType,
+	jsonPatchType:  types.JSONPatchType,
+}
+
+// Options contains the input to the patch command.
+type Options struct {
+	PrintFlags *genericclioptions.PrintFlags
+	ToPrinter  func(string) (printers.ResourcePrinter, error)
+
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	Patch     string
+	PatchFile string
+	PatchType string
+
+	client    *client.Client
+	registry  *registry.Registry
+	resource  *registry.Resource
+	namespace string
+	name      string
+}
+
+// NewOptions returns an Options for the patch command with merge patch as the default patch type.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *Options {
+	return &Options{
+		PrintFlags: genericclioptions.NewPrintFlags("patched").WithTypeSetter(scheme.Scheme),
+		PatchType:  mergePatchType,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		registry:      resourceRegistry,
+	}
+}
+
+// NewCmd creates a command object for the "patch" action, which updates fields of a resource.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourceRegistry)
+
+	cmd := &cobra.Command{
+		Use:                   "patch TYPE NAME [-p PATCH | --patch-file FILE] [--type merge|json]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Update fields of a resource"),
+		Long:                  patchLong,
+		Example:               fmt.Sprintf(patchExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	cmd.Flags().StringVarP(&o.Patch, "patch", "p", o.Patch, "The patch to be applied to the resource JSON file.")
+	cmd.Flags().StringVar(&o.PatchFile, "patch-file", o.PatchFile, "A file containing a patch to be applied to the resource.")
+	cmd.Flags().StringVar(&o.PatchType, "type", o.PatchType,
+		fmt.Sprintf("The type of patch being provided; one of %v", []string{mergePatchType, jsonPatchType}))
+
+	cmd.ValidArgsFunction = completion.ResourceTypesAndNames(clientFactory, resourceRegistry, registry.VerbPatch, false)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("type", completion.Values(mergePatchType, jsonPatchType)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	var names []string
+
+	o.resource, names, err = o.registry.ResourceFromArgs(args, registry.VerbPatch)
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one NAME is required, got %d", len(names))
+	}
+	o.name = names[0]
+
+	if o.resource.Namespaced() {
+		o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return err
+		}
+	}
+
+	o.PatchType = strings.ToLower(o.PatchType)
+
+	o.ToPrinter = func(operation string) (printers.ResourcePrinter, error) {
+		o.PrintFlags.NamePrintFlags.Operation = operation
+		return o.PrintFlags.ToPrinter()
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if len(o.Patch) > 0 && len(o.PatchFile) > 0 {
+		return cmdutil.UsageErrorf(cmd, "cannot specify --patch and --patch-file together")
+	}
+	if len(o.Patch) == 0 && len(o.PatchFile) == 0 {
+		return cmdutil.UsageErrorf(cmd, "must specify --patch or --patch-file containing the contents of the patch")
+	}
+
+	if _, found := patchTypes[o.PatchType]; !found {
+		return fmt.Errorf("%w: %q, --type must be one of %v", errUnknownPatchType, o.PatchType,
+			[]string{mergePatchType, jsonPatchType})
+	}
+
+	return nil
+}
+
+// Run performs the patch operation.
+func (o *Options) Run() error {
+	patch, err := o.readPatch()
+	if err != nil {
+		return err
+	}
+
+	patched, err := o.client.Patch(context.TODO(), o.resource.Path, o.namespace, o.name, patchTypes[o.PatchType],
+		patch)
+	if err != nil {
+		return err
+	}
+
+	printer, err := o.ToPrinter("patched")
+	if err != nil {
+		return err
+	}
+
+	return printer.PrintObj(o.patchedObject(patched), o.Out)
+}
+
+// readPatch reads the patch from the flags and converts it to JSON
+func (o *Options) readPatch() ([]byte, error) {
+	patch := []byte(o.Patch)
+	if len(o.PatchFile) > 0 {
+		var err error
+		if patch, err = ioutil.ReadFile(o.PatchFile); err != nil {
+			return nil, fmt.Errorf("unable to read patch file: %w", err)
+		}
+	}
+
+	patchJSON, err := yaml.YAMLToJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %q: %w", string(patch), err)
+	}
+
+	if o.PatchType == jsonPatchType {
+		if _, err := jsonpatch.DecodePatch(patchJSON); err != nil {
+			return nil, fmt.Errorf("unable to parse JSON patch: %w", err)
+		}
+	}
+
+	return patchJSON, nil
+}
+
+// patchedObject returns the object returned by Non-K8s API, or a stub of the patched object if none was returned
+func (o *Options) patchedObject(patched *unstructured.Unstructured) runtime.Object {
+	if patched != nil {
+		return patched
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(o.resource.Mapping.GroupVersionKind)
+	obj.SetNamespace(o.namespace)
+	obj.SetName(o.name)
+
+	return obj
+}
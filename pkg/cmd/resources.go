@@ -0,0 +1,279 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/get"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	policyGroupName      = "policy.open-cluster-management.io"
+	policyVersion        = "v1"
+	appsGroupName        = "apps.open-cluster-management.io"
+	placementRuleVersion = "v1"
+	placementVersion     = "v1beta1"
+	maxClustersToShow    = 5
+	compliant            = "Compliant"
+	nonCompliant         = "NonCompliant"
+	maxSubjectsToShow    = 3
+	// placementLabel is the label of placement decisions holding the name of their placement
+	placementLabel = "cluster.open-cluster-management.io/placement"
+	// eventsPath is the path of the events related to managed clusters in Non-K8s API
+	eventsPath = "events"
+	// discoveryPath is the path of the discovery endpoint of Non-K8s API, listing the resources it serves
+	discoveryPath = "api-resources"
+)
+
+func newMapping(gv schema.GroupVersion, resource, kind string, scope meta.RESTScope) *meta.RESTMapping {
+	return &meta.RESTMapping{
+		Resource:         gv.WithResource(resource),
+		GroupVersionKind: gv.WithKind(kind),
+		Scope:            scope,
+	}
+}
+
+// newRegistry returns a registry of the resources served by Non-K8s API, managed clusters first
+// as the default resource
+func newRegistry() *registry.Registry {
+	resourceRegistry := registry.New()
+
+	for _, resource := range newResources() {
+		cmdutil.CheckErr(resourceRegistry.Register(resource))
+	}
+
+	return resourceRegistry
+}
+
+func newResources() []*registry.Resource {
+	return []*registry.Resource{
+		{
+			Mapping:      newMapping(clusterv1.GroupVersion, "managedclusters", "ManagedCluster", rootScope),
+			Path:         client.ManagedClustersPath,
+			SingularName: "managedcluster",
+			ShortNames:   []string{"mcl", "mcls"},
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbWatch, registry.VerbPatch},
+		},
+		{
+			Mapping: newMapping(schema.GroupVersion{Group: policyGroupName, Version: policyVersion},
+				"policies", "Policy", namespaceScope),
+			Path:         "policies",
+			SingularName: "policy",
+			ShortNames:   []string{"plc"},
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbWatch, registry.VerbPatch},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "Namespace", Type: "string"},
+				{Name: "Name", Type: "string", Format: "name"},
+				{Name: "Remediation Action", Type: "string"},
+				{Name: "Compliance State", Type: "string"},
+				{Name: "Compliant", Type: "integer"},
+				{Name: "NonCompliant", Type: "integer"},
+				{Name: "Unknown", Type: "integer"},
+				{Name: "Age", Type: "string"},
+			},
+			Cells: policyCells,
+		},
+		{
+			Mapping: newMapping(schema.GroupVersion{Group: policyGroupName, Version: policyVersion},
+				"placementbindings", "PlacementBinding", namespaceScope),
+			Path:         "placementbindings",
+			SingularName: "placementbinding",
+			ShortNames:   []string{"pb"},
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbWatch},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "Namespace", Type: "string"},
+				{Name: "Name", Type: "string", Format: "name"},
+				{Name: "Placement", Type: "string"},
+				{Name: "Subjects", Type: "string"},
+				{Name: "Age", Type: "string"},
+			},
+			Cells: placementBindingCells,
+		},
+		{
+			Mapping: newMapping(schema.GroupVersion{Group: clusterv1.GroupName, Version: placementVersion},
+				"placements", "Placement", namespaceScope),
+			Path:         "placements",
+			SingularName: "placement",
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbWatch},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "Namespace", Type: "string"},
+				{Name: "Name", Type: "string", Format: "name"},
+				{Name: "Selected", Type: "integer"},
+				{Name: "Selected Clusters", Type: "string"},
+				{Name: "Age", Type: "string"},
+			},
+			Cells:       placementCells,
+			RelatedPath: "placementdecisions",
+		},
+		{
+			Mapping: newMapping(schema.GroupVersion{Group: clusterv1.GroupName, Version: placementVersion},
+				"placementdecisions", "PlacementDecision", namespaceScope),
+			Path:         "placementdecisions",
+			SingularName: "placementdecision",
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbWatch},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "Namespace", Type: "string"},
+				{Name: "Name", Type: "string", Format: "name"},
+				{Name: "Placement", Type: "string"},
+				{Name: "Decisions", Type: "integer"},
+				{Name: "Selected Clusters", Type: "string"},
+				{Name: "Age", Type: "string"},
+			},
+			Cells: placementDecisionCells,
+		},
+		{
+			Mapping: newMapping(schema.GroupVersion{Group: appsGroupName, Version: placementRuleVersion},
+				"placementrules", "PlacementRule", namespaceScope),
+			Path:              "placementrules",
+			SingularName:      "placementrule",
+			ShortNames:        []string{"plr"},
+			Verbs:             []string{registry.VerbGet, registry.VerbList, registry.VerbWatch},
+			ColumnDefinitions: placementRuleColumnDefinitions,
+			Cells:             placementRuleCells,
+		},
+	}
+}
+
+// policyCells returns the compliance of the policy and the numbers of the clusters by their compliance
+// state, as reported by all the leaf hubs
+func policyCells(obj *unstructured.Unstructured, _ []*unstructured.Unstructured) []interface{} {
+	remediationAction, _, _ := unstructured.NestedString(obj.Object, "spec", "remediationAction")
+	complianceState, _, _ := unstructured.NestedString(obj.Object, "status", "compliant")
+	clusterStatuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "status")
+
+	compliantCount, nonCompliantCount, unknownCount := 0, 0, 0
+
+	for _, clusterStatus := range clusterStatuses {
+		clusterStatusMap, ok := clusterStatus.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch clusterStatusMap["compliant"] {
+		case compliant:
+			compliantCount++
+		case nonCompliant:
+			nonCompliantCount++
+		default:
+			unknownCount++
+		}
+	}
+
+	return []interface{}{
+		obj.GetNamespace(), obj.GetName(), pluginutil.ValueOrNone(remediationAction),
+		pluginutil.ValueOrNone(complianceState), compliantCount, nonCompliantCount, unknownCount, get.Age(obj),
+	}
+}
+
+func placementBindingCells(obj *unstructured.Unstructured, _ []*unstructured.Unstructured) []interface{} {
+	placementKind, _, _ := unstructured.NestedString(obj.Object, "placementRef", "kind")
+	placementName, _, _ := unstructured.NestedString(obj.Object, "placementRef", "name")
+	subjects, _, _ := unstructured.NestedSlice(obj.Object, "subjects")
+
+	placement := pluginutil.NoneValue
+	if placementName != "" {
+		placement = fmt.Sprintf("%s/%s", placementKind, placementName)
+	}
+
+	subjectNames := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		if subjectMap, ok := subject.(map[string]interface{}); ok {
+			subjectNames = append(subjectNames, fmt.Sprintf("%v/%v", subjectMap["kind"], subjectMap["name"]))
+		}
+	}
+
+	return []interface{}{
+		obj.GetNamespace(), obj.GetName(), placement, joinWithLimit(subjectNames, maxSubjectsToShow), get.Age(obj),
+	}
+}
+
+// placementRuleColumnDefinitions are the columns of placement rules
+var placementRuleColumnDefinitions = []metav1.TableColumnDefinition{
+	{Name: "Namespace", Type: "string"},
+	{Name: "Name", Type: "string", Format: "name"},
+	{Name: "Decisions", Type: "integer"},
+	{Name: "Selected Clusters", Type: "string"},
+	{Name: "Age", Type: "string"},
+}
+
+// placementCells returns the number of the clusters selected by a placement and their names. The decisions of a
+// placement are kept in the placement decisions labeled with its name, in its namespace, not in its status.
+func placementCells(obj *unstructured.Unstructured, placementDecisions []*unstructured.Unstructured) []interface{} {
+	numberOfSelectedClusters, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberOfSelectedClusters")
+
+	var clusterNames []string
+	for _, placementDecision := range placementDecisions {
+		if placementDecision.GetNamespace() == obj.GetNamespace() &&
+			placementDecision.GetLabels()[placementLabel] == obj.GetName() {
+			clusterNames = append(clusterNames, decisionClusterNames(placementDecision)...)
+		}
+	}
+
+	return []interface{}{
+		obj.GetNamespace(), obj.GetName(), numberOfSelectedClusters, joinWithLimit(clusterNames, maxClustersToShow),
+		get.Age(obj),
+	}
+}
+
+// placementDecisionCells returns the placement, the number of the decisions and the names of the selected
+// clusters of a placement decision
+func placementDecisionCells(obj *unstructured.Unstructured, _ []*unstructured.Unstructured) []interface{} {
+	clusterNames := decisionClusterNames(obj)
+
+	return []interface{}{
+		obj.GetNamespace(), obj.GetName(), pluginutil.ValueOrNone(obj.GetLabels()[placementLabel]),
+		int64(len(clusterNames)), joinWithLimit(clusterNames, maxClustersToShow), get.Age(obj),
+	}
+}
+
+// placementRuleCells returns the number of the decisions and the names of the selected clusters of a placement
+// rule
+func placementRuleCells(obj *unstructured.Unstructured, _ []*unstructured.Unstructured) []interface{} {
+	clusterNames := decisionClusterNames(obj)
+
+	return []interface{}{
+		obj.GetNamespace(), obj.GetName(), int64(len(clusterNames)), joinWithLimit(clusterNames, maxClustersToShow),
+		get.Age(obj),
+	}
+}
+
+// decisionClusterNames returns the names of the clusters of the decisions in the status of a placement rule or a
+// placement decision
+func decisionClusterNames(obj *unstructured.Unstructured) []string {
+	decisions, _, _ := unstructured.NestedSlice(obj.Object, "status", "decisions")
+
+	clusterNames := make([]string, 0, len(decisions))
+	for _, decision := range decisions {
+		if decisionMap, ok := decision.(map[string]interface{}); ok {
+			if clusterName, ok := decisionMap["clusterName"].(string); ok {
+				clusterNames = append(clusterNames, clusterName)
+			}
+		}
+	}
+
+	return clusterNames
+}
+
+// joinWithLimit joins the values, showing at most limit values and the number of the rest ones
+func joinWithLimit(values []string, limit int) string {
+	if len(values) == 0 {
+		return pluginutil.NoneValue
+	}
+	if len(values) <= limit {
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprintf("%s + %d more...", strings.Join(values[:limit], ","), len(values)-limit)
+}
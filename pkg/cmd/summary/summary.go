@@ -0,0 +1,265 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/snapshot"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	summaryLong = templates.LongDesc(i18n.T(`
+		Display a summary of the managed clusters of the hub of hubs.
+
+		Prints the numbers of the managed clusters by their availability, joined and accepted
+		state, Kubernetes and OpenShift versions, cloud, vendor and leaf hub.`))
+
+	summaryExample = templates.Examples(i18n.T(`
+		# Display a summary of the managed clusters
+		%[1]s summary
+
+		# Display a summary of the managed clusters in JSON output format
+		%[1]s summary -o json
+
+		# Display a summary of the managed clusters of a snapshot exported to the directory 'fleet-snapshot'
+		%[1]s summary --from-snapshot fleet-snapshot`))
+
+	errUnknownOutputFormat = errors.New("unknown output format")
+)
+
+const (
+	jsonOutput = "json"
+	yamlOutput = "yaml"
+
+	cloudLabel  = "cloud"
+	vendorLabel = "vendor"
+)
+
+// Options contains the input to the summary command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	OutputFormat string
+	FromSnapshot string
+
+	client *client.Client
+}
+
+// fleetSummary holds the numbers of the managed clusters by each of their properties
+type fleetSummary struct {
+	ManagedClusters    int            `json:"managedClusters"`
+	Availability       map[string]int `json:"availability"`
+	Joined             map[string]int `json:"joined"`
+	Accepted           map[string]int `json:"accepted"`
+	KubernetesVersions map[string]int `json:"kubernetesVersions"`
+	OpenShiftVersions  map[string]int `json:"openshiftVersions"`
+	Clouds             map[string]int `json:"clouds"`
+	Vendors            map[string]int `json:"vendors"`
+	LeafHubs           map[string]int `json:"leafHubs"`
+}
+
+// NewOptions returns an Options for the summary command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+	}
+}
+
+// NewCmd creates a command object for the "summary" action, which summarizes the managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory,
+	streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(clientFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "summary [(-o|--output=)json|yaml]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Display a summary of the managed clusters"),
+		Long:                  summaryLong,
+		Example:               fmt.Sprintf(summaryExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", o.OutputFormat,
+		fmt.Sprintf("Output format. One of: %s.", strings.Join([]string{jsonOutput, yamlOutput}, "|")))
+	cmd.Flags().StringVar(&o.FromSnapshot, "from-snapshot", o.FromSnapshot,
+		"If present, read the managed clusters from the snapshot in this directory, created by the export command, instead of the hub.")
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Values(jsonOutput, yamlOutput)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	if o.FromSnapshot != "" {
+		return nil
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	switch o.OutputFormat {
+	case "", jsonOutput, yamlOutput:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, allowed formats are: %s", errUnknownOutputFormat, o.OutputFormat,
+			strings.Join([]string{jsonOutput, yamlOutput}, ","))
+	}
+}
+
+// Run performs the summary operation.
+func (o *Options) Run() error {
+	managedClusters, err := o.getManagedClusters()
+	if err != nil {
+		return err
+	}
+
+	summary := summarize(managedClusters)
+
+	switch o.OutputFormat {
+	case jsonOutput:
+		data, err := json.MarshalIndent(summary, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+	case yamlOutput:
+		data, err := yaml.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.Out, string(data))
+	default:
+		printSummary(o.Out, summary)
+	}
+
+	return nil
+}
+
+// getManagedClusters returns the managed clusters from the snapshot if --from-snapshot is specified,
+// or else from Non-K8s API
+func (o *Options) getManagedClusters() ([]*clusterv1.ManagedCluster, error) {
+	if o.FromSnapshot == "" {
+		return o.client.ListManagedClusters(context.TODO())
+	}
+
+	objs, err := snapshot.GetResourceObjects(o.FromSnapshot, client.ManagedClustersPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return pluginutil.ToManagedClusters(objs)
+}
+
+func summarize(managedClusters []*clusterv1.ManagedCluster) *fleetSummary {
+	summary := &fleetSummary{
+		ManagedClusters:    len(managedClusters),
+		Availability:       map[string]int{},
+		Joined:             map[string]int{},
+		Accepted:           map[string]int{},
+		KubernetesVersions: map[string]int{},
+		OpenShiftVersions:  map[string]int{},
+		Clouds:             map[string]int{},
+		Vendors:            map[string]int{},
+		LeafHubs:           map[string]int{},
+	}
+
+	for _, managedCluster := range managedClusters {
+		labels := managedCluster.GetLabels()
+
+		summary.Availability[string(pluginutil.GetConditionStatus(managedCluster,
+			clusterv1.ManagedClusterConditionAvailable))]++
+		summary.Joined[string(pluginutil.GetConditionStatus(managedCluster,
+			clusterv1.ManagedClusterConditionJoined))]++
+		summary.Accepted[string(pluginutil.GetConditionStatus(managedCluster,
+			clusterv1.ManagedClusterConditionHubAccepted))]++
+		summary.KubernetesVersions[pluginutil.ValueOrNone(managedCluster.Status.Version.Kubernetes)]++
+		summary.OpenShiftVersions[pluginutil.ValueOrNone(pluginutil.GetOpenShiftVersion(managedCluster))]++
+		summary.Clouds[pluginutil.ValueOrNone(labels[cloudLabel])]++
+		summary.Vendors[pluginutil.ValueOrNone(labels[vendorLabel])]++
+		summary.LeafHubs[pluginutil.ValueOrNone(pluginutil.GetLeafHubName(managedCluster))]++
+	}
+
+	return summary
+}
+
+func printSummary(out io.Writer, summary *fleetSummary) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Managed Clusters:\t%d\n", summary.ManagedClusters)
+	fmt.Fprintf(w, "Available:\t%s\n", formatCounts(summary.Availability))
+	fmt.Fprintf(w, "Joined:\t%s\n", formatCounts(summary.Joined))
+	fmt.Fprintf(w, "Accepted:\t%s\n", formatCounts(summary.Accepted))
+	fmt.Fprintf(w, "Kubernetes Versions:\t%s\n", formatCounts(summary.KubernetesVersions))
+	fmt.Fprintf(w, "OpenShift Versions:\t%s\n", formatCounts(summary.OpenShiftVersions))
+	fmt.Fprintf(w, "Clouds:\t%s\n", formatCounts(summary.Clouds))
+	fmt.Fprintf(w, "Vendors:\t%s\n", formatCounts(summary.Vendors))
+	fmt.Fprintf(w, "Leaf Hubs:\t%s\n", formatCounts(summary.LeafHubs))
+}
+
+// formatCounts formats the counts as value=count pairs, the most frequent values first
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return pluginutil.NoneValue
+	}
+
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	pairs := make([]string, 0, len(values))
+	for _, value := range values {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", value, counts[value]))
+	}
+
+	return strings.Join(pairs, "  ")
+}
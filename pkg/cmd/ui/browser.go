@@ -0,0 +1,688 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// The ANSI escape sequences drawing the screen, supported by any terminal emulating a VT100
+const (
+	clearLine    = "\x1b[K"
+	clearBelow   = "\x1b[J"
+	cursorHome   = "\x1b[H"
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+)
+
+const columnSpacing = 3
+
+var errInvalidChange = errors.New("invalid change, must be key=value or key-")
+
+// mode is the screen displayed by the browser
+type mode int
+
+const (
+	listMode mode = iota
+	filterMode
+	describeMode
+	actionsMode
+	inputMode
+)
+
+// column is a column of the list of the managed clusters
+type column struct {
+	header string
+	value  func(obj *unstructured.Unstructured) string
+	// sortValue is the value the managed clusters are sorted by, the value of the column if nil
+	sortValue func(obj *unstructured.Unstructured) string
+}
+
+var columns = []column{
+	{header: "NAME", value: func(obj *unstructured.Unstructured) string { return obj.GetName() }},
+	{header: "HUB", value: func(obj *unstructured.Unstructured) string {
+		return pluginutil.ValueOrNone(pluginutil.GetLeafHubName(obj))
+	}},
+	{header: "JOINED", value: conditionStatus(clusterv1.ManagedClusterConditionJoined)},
+	{header: "AVAILABLE", value: conditionStatus(clusterv1.ManagedClusterConditionAvailable)},
+	{header: "KUBERNETES", value: func(obj *unstructured.Unstructured) string {
+		version, _, _ := unstructured.NestedString(obj.Object, "status", "version", "kubernetes")
+		return pluginutil.ValueOrNone(version)
+	}},
+	{
+		header: "AGE",
+		value: func(obj *unstructured.Unstructured) string {
+			return duration.HumanDuration(time.Since(obj.GetCreationTimestamp().Time))
+		},
+		// the youngest managed clusters first, as by the smallest age
+		sortValue: func(obj *unstructured.Unstructured) string {
+			return fmt.Sprintf("%020d", time.Now().Unix()-obj.GetCreationTimestamp().Unix())
+		},
+	},
+	{header: "LABELS", value: func(obj *unstructured.Unstructured) string {
+		return pluginutil.ValueOrNone(labels.Set(obj.GetLabels()).String())
+	}},
+}
+
+// action is an action of the actions menu, which changes the metadata of a managed cluster
+type action struct {
+	name     string
+	shortcut rune
+	// field is the field of the metadata changed by the action
+	field string
+	// done describes the managed cluster after the action
+	done string
+}
+
+var actions = []action{
+	{name: "Label", shortcut: 'l', field: "labels", done: "labeled"},
+	{name: "Annotate", shortcut: 'a', field: "annotations", done: "annotated"},
+}
+
+// fleet reads and changes the managed clusters browsed
+type fleet interface {
+	// describe returns the description of the managed cluster, as printed by the describe command
+	describe(obj *unstructured.Unstructured) (string, error)
+	// patch applies the merge patch to the managed cluster, and returns the patched managed cluster if returned
+	// by Non-K8s API
+	patch(name string, patch []byte) (*unstructured.Unstructured, error)
+}
+
+// browser holds the state of the screen of the ui command, changed by the keys and the updates of the managed
+// clusters
+type browser struct {
+	fleet   fleet
+	context string
+
+	objects map[string]*unstructured.Unstructured
+	// rows are the managed clusters matching the filter, in the sort order
+	rows []*unstructured.Unstructured
+
+	mode           mode
+	filter         string
+	sortColumn     int
+	sortDescending bool
+	selected       int
+	top            int
+
+	// target is the name of the managed cluster described or changed by an action
+	target      string
+	action      int
+	input       string
+	description []string
+	describeTop int
+
+	// source describes how the managed clusters are refreshed, e.g. by the watch stream
+	source string
+	// message is the result of the last action or the last error, shown until the next key
+	message string
+
+	width  int
+	height int
+	quit   bool
+}
+
+func newBrowser(fleet fleet, contextName string) *browser {
+	return &browser{
+		fleet:   fleet,
+		context: contextName,
+		objects: map[string]*unstructured.Unstructured{},
+	}
+}
+
+// setObjects replaces the managed clusters by the listed managed clusters
+func (b *browser) setObjects(objs []*unstructured.Unstructured) {
+	b.objects = make(map[string]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		b.objects[obj.GetName()] = obj
+	}
+
+	b.refreshRows()
+}
+
+// applyEvent updates the managed clusters by an event of the watch stream
+func (b *browser) applyEvent(eventType watch.EventType, obj *unstructured.Unstructured) {
+	if eventType == watch.Deleted {
+		delete(b.objects, obj.GetName())
+	} else {
+		b.objects[obj.GetName()] = obj
+	}
+
+	b.refreshRows()
+}
+
+// refreshRows filters and sorts the managed clusters, keeping the selected managed cluster selected
+func (b *browser) refreshRows() {
+	selectedName := ""
+	if selected := b.selectedObject(); selected != nil {
+		selectedName = selected.GetName()
+	}
+
+	b.rows = b.rows[:0]
+	for _, obj := range b.objects {
+		if matches(obj, b.filter) {
+			b.rows = append(b.rows, obj)
+		}
+	}
+
+	sortValue := columns[b.sortColumn].sortValue
+	if sortValue == nil {
+		sortValue = columns[b.sortColumn].value
+	}
+
+	sort.Slice(b.rows, func(i, j int) bool {
+		vi, vj := sortValue(b.rows[i]), sortValue(b.rows[j])
+		if vi == vj {
+			return b.rows[i].GetName() < b.rows[j].GetName()
+		}
+		return (vi < vj) != b.sortDescending
+	})
+
+	for i, obj := range b.rows {
+		if obj.GetName() == selectedName {
+			b.selected = i
+			return
+		}
+	}
+
+	b.moveSelection(0)
+}
+
+// matches returns true if the filter is a part of the name or of a label key=value of the managed cluster,
+// ignoring case
+func matches(obj *unstructured.Unstructured, filter string) bool {
+	filter = strings.ToLower(filter)
+	if strings.Contains(strings.ToLower(obj.GetName()), filter) {
+		return true
+	}
+
+	for key, value := range obj.GetLabels() {
+		if strings.Contains(strings.ToLower(key+"="+value), filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *browser) selectedObject() *unstructured.Unstructured {
+	if b.selected < 0 || b.selected >= len(b.rows) {
+		return nil
+	}
+
+	return b.rows[b.selected]
+}
+
+// moveSelection moves the selection by the delta, within the rows
+func (b *browser) moveSelection(delta int) {
+	b.selected += delta
+	if b.selected >= len(b.rows) {
+		b.selected = len(b.rows) - 1
+	}
+	if b.selected < 0 {
+		b.selected = 0
+	}
+}
+
+// handleKey changes the state of the browser by the key pressed in the current mode
+func (b *browser) handleKey(k key) {
+	if k.special == keyInterrupt {
+		b.quit = true
+		return
+	}
+
+	b.message = ""
+
+	switch b.mode {
+	case listMode:
+		b.handleListKey(k)
+	case filterMode:
+		b.handleFilterKey(k)
+	case describeMode:
+		b.handleDescribeKey(k)
+	case actionsMode:
+		b.handleActionsKey(k)
+	case inputMode:
+		b.handleInputKey(k)
+	}
+}
+
+func (b *browser) handleListKey(k key) {
+	page := b.visibleRows()
+
+	switch {
+	case k.r == 'q':
+		b.quit = true
+	case k.special == keyEscape:
+		if b.filter != "" {
+			b.filter = ""
+			b.refreshRows()
+		}
+	case k.special == keyUp || k.r == 'k':
+		b.moveSelection(-1)
+	case k.special == keyDown || k.r == 'j':
+		b.moveSelection(1)
+	case k.special == keyPageUp:
+		b.moveSelection(-page)
+	case k.special == keyPageDown || k.r == ' ':
+		b.moveSelection(page)
+	case k.special == keyHome || k.r == 'g':
+		b.moveSelection(-len(b.rows))
+	case k.special == keyEnd || k.r == 'G':
+		b.moveSelection(len(b.rows))
+	case k.r == '/':
+		b.mode = filterMode
+	case k.r >= '1' && k.r < '1'+rune(len(columns)):
+		b.sortBy(int(k.r - '1'))
+	case k.r == 's' || k.special == keyRight:
+		b.sortBy((b.sortColumn + 1) % len(columns))
+	case k.special == keyLeft:
+		b.sortBy((b.sortColumn + len(columns) - 1) % len(columns))
+	case k.r == 'S':
+		b.sortBy(b.sortColumn)
+	case k.special == keyEnter || k.r == 'd':
+		b.describe()
+	case k.r == 'a':
+		if obj := b.selectedObject(); obj != nil {
+			b.mode = actionsMode
+			b.action = 0
+			b.target = obj.GetName()
+		}
+	}
+}
+
+// sortBy sorts the managed clusters by the column, in the reverse order if they are already sorted by it
+func (b *browser) sortBy(sortColumn int) {
+	if sortColumn == b.sortColumn {
+		b.sortDescending = !b.sortDescending
+	} else {
+		b.sortColumn = sortColumn
+		b.sortDescending = false
+	}
+
+	b.refreshRows()
+}
+
+func (b *browser) handleFilterKey(k key) {
+	switch {
+	case k.special == keyEnter:
+		b.mode = listMode
+		return
+	case k.special == keyEscape:
+		b.filter = ""
+		b.mode = listMode
+	case k.special == keyBackspace:
+		if runes := []rune(b.filter); len(runes) > 0 {
+			b.filter = string(runes[:len(runes)-1])
+		}
+	case k.r != 0:
+		b.filter += string(k.r)
+	default:
+		return
+	}
+
+	b.refreshRows()
+}
+
+// describe shows the description of the selected managed cluster
+func (b *browser) describe() {
+	obj := b.selectedObject()
+	if obj == nil {
+		return
+	}
+
+	description, err := b.fleet.describe(obj)
+	if err != nil {
+		b.message = err.Error()
+		return
+	}
+
+	b.description = strings.Split(strings.TrimRight(description, "\n"), "\n")
+	b.describeTop = 0
+	b.target = obj.GetName()
+	b.mode = describeMode
+}
+
+func (b *browser) handleDescribeKey(k key) {
+	page := b.height - 2
+
+	switch {
+	case k.special == keyEscape || k.r == 'q':
+		b.mode = listMode
+	case k.special == keyUp || k.r == 'k':
+		b.describeTop--
+	case k.special == keyDown || k.r == 'j':
+		b.describeTop++
+	case k.special == keyPageUp:
+		b.describeTop -= page
+	case k.special == keyPageDown || k.r == ' ':
+		b.describeTop += page
+	case k.special == keyHome || k.r == 'g':
+		b.describeTop = 0
+	case k.special == keyEnd || k.r == 'G':
+		b.describeTop = len(b.description)
+	}
+
+	if b.describeTop > len(b.description)-page {
+		b.describeTop = len(b.description) - page
+	}
+	if b.describeTop < 0 {
+		b.describeTop = 0
+	}
+}
+
+func (b *browser) handleActionsKey(k key) {
+	switch {
+	case k.special == keyEscape || k.r == 'q':
+		b.mode = listMode
+	case k.special == keyUp || k.r == 'k':
+		b.action = (b.action + len(actions) - 1) % len(actions)
+	case k.special == keyDown || k.r == 'j':
+		b.action = (b.action + 1) % len(actions)
+	case k.special == keyEnter:
+		b.mode = inputMode
+		b.input = ""
+	default:
+		for i, action := range actions {
+			if k.r == action.shortcut {
+				b.action = i
+				b.mode = inputMode
+				b.input = ""
+			}
+		}
+	}
+}
+
+func (b *browser) handleInputKey(k key) {
+	switch {
+	case k.special == keyEscape:
+		b.mode = listMode
+	case k.special == keyEnter:
+		b.mode = listMode
+		b.applyAction()
+	case k.special == keyBackspace:
+		if runes := []rune(b.input); len(runes) > 0 {
+			b.input = string(runes[:len(runes)-1])
+		}
+	case k.r != 0:
+		b.input += string(k.r)
+	}
+}
+
+// applyAction patches the selected managed cluster by the changes of the input, key=value to set a key and key-
+// to remove it
+func (b *browser) applyAction() {
+	if strings.TrimSpace(b.input) == "" {
+		return
+	}
+
+	action := actions[b.action]
+
+	patch, err := metadataPatch(action.field, strings.Fields(b.input))
+	if err != nil {
+		b.message = err.Error()
+		return
+	}
+
+	patched, err := b.fleet.patch(b.target, patch)
+	if err != nil {
+		b.message = err.Error()
+		return
+	}
+
+	if patched != nil {
+		b.applyEvent(watch.Modified, patched)
+	}
+
+	b.message = fmt.Sprintf("managedcluster/%s %s", b.target, action.done)
+}
+
+// metadataPatch returns a merge patch of the field of the metadata, setting the keys of the key=value changes
+// and removing the keys of the key- changes
+func metadataPatch(field string, changes []string) ([]byte, error) {
+	values := map[string]interface{}{}
+
+	for _, change := range changes {
+		if key := strings.TrimSuffix(change, "-"); key != change && key != "" && !strings.Contains(key, "=") {
+			values[key] = nil
+			continue
+		}
+
+		parts := strings.SplitN(change, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%w: %q", errInvalidChange, change)
+		}
+		values[parts[0]] = parts[1]
+	}
+
+	return json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{field: values}})
+}
+
+// visibleRows returns the number of the rows of managed clusters fitting on the screen, below the title and
+// the headers and above the footer
+func (b *browser) visibleRows() int {
+	if rows := b.height - 3; rows > 1 {
+		return rows
+	}
+
+	return 1
+}
+
+// render draws the screen of the current mode, clearing the rest of the screen
+func (b *browser) render(out io.Writer) {
+	var lines []string
+
+	if b.mode == describeMode {
+		lines = b.describeLines()
+	} else {
+		lines = b.listLines()
+	}
+
+	var frame strings.Builder
+
+	frame.WriteString(cursorHome)
+
+	for i, line := range lines {
+		if i > 0 {
+			// the terminal is in raw mode, so lines need a carriage return
+			frame.WriteString("\r\n")
+		}
+		frame.WriteString(line)
+		frame.WriteString(clearLine)
+	}
+
+	frame.WriteString(clearBelow)
+
+	fmt.Fprint(out, frame.String())
+}
+
+// listLines returns the lines of the list of the managed clusters, with the actions menu or the input of an
+// action over its last rows
+func (b *browser) listLines() []string {
+	title := fmt.Sprintf(" Managed clusters [%d/%d]  context: %s  sort: %s", len(b.rows), len(b.objects),
+		pluginutil.ValueOrNone(b.context), columns[b.sortColumn].header)
+	if b.sortDescending {
+		title += " (desc)"
+	}
+	if b.filter != "" {
+		title += fmt.Sprintf("  filter: %s", b.filter)
+	}
+	if b.source != "" {
+		title += "  " + b.source
+	}
+
+	lines := []string{b.styled(reverseVideo, title)}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+
+	cells := append(make([][]string, 0, len(b.rows)+1), headers)
+
+	for _, obj := range b.rows {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.value(obj)
+		}
+		cells = append(cells, row)
+	}
+
+	table := formatTable(cells)
+	lines = append(lines, b.styled(bold, table[0]))
+
+	visible := b.visibleRows()
+	if b.selected < b.top {
+		b.top = b.selected
+	}
+	if b.selected >= b.top+visible {
+		b.top = b.selected - visible + 1
+	}
+
+	for i := b.top; i < len(b.rows) && i < b.top+visible; i++ {
+		if i == b.selected {
+			lines = append(lines, b.styled(reverseVideo, table[i+1]))
+		} else {
+			lines = append(lines, b.truncate(table[i+1]))
+		}
+	}
+
+	if len(b.rows) == 0 {
+		lines = append(lines, " No managed clusters found")
+	}
+
+	for len(lines) < b.height-1 {
+		lines = append(lines, "")
+	}
+
+	if b.mode == actionsMode {
+		menu := b.actionsMenu()
+		copy(lines[len(lines)-len(menu):], menu)
+	}
+
+	return append(lines, b.footer())
+}
+
+// actionsMenu returns the lines of the actions menu, the selected action highlighted
+func (b *browser) actionsMenu() []string {
+	menu := []string{b.styled(bold, fmt.Sprintf(" Actions on managedcluster/%s", b.target))}
+
+	for i, action := range actions {
+		line := fmt.Sprintf("   %s (%c)", action.name, action.shortcut)
+		if i == b.action {
+			menu = append(menu, b.styled(reverseVideo, line))
+		} else {
+			menu = append(menu, b.truncate(line))
+		}
+	}
+
+	return menu
+}
+
+// footer returns the last line: the message, the filter or the input being typed, or else the help of the keys
+func (b *browser) footer() string {
+	switch {
+	case b.message != "":
+		return b.truncate(" " + b.message)
+	case b.mode == filterMode:
+		return b.truncate(" /" + b.filter + "_")
+	case b.mode == inputMode:
+		action := actions[b.action]
+		return b.truncate(fmt.Sprintf(" %s managedcluster/%s (key=value, key- to remove): %s_",
+			strings.ToLower(action.name), b.target, b.input))
+	case b.mode == actionsMode:
+		return b.truncate(" up/down select  enter choose  esc cancel")
+	default:
+		return b.truncate(fmt.Sprintf(" / filter  1-%d,s,S sort  enter describe  a actions  q quit", len(columns)))
+	}
+}
+
+// describeLines returns the lines of the description of the managed cluster, from the scroll position
+func (b *browser) describeLines() []string {
+	lines := []string{b.styled(reverseVideo, fmt.Sprintf(" Describe managedcluster/%s", b.target))}
+
+	for i := b.describeTop; i < len(b.description) && len(lines) < b.height-1; i++ {
+		lines = append(lines, b.truncate(b.description[i]))
+	}
+
+	for len(lines) < b.height-1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines, b.truncate(" up/down scroll  esc back"))
+}
+
+// styled returns the line in the style, padded to the width of the screen
+func (b *browser) styled(style, line string) string {
+	line = b.truncate(line)
+	if padding := b.width - len([]rune(line)); padding > 0 {
+		line += strings.Repeat(" ", padding)
+	}
+
+	return style + line + resetStyle
+}
+
+// truncate cuts the line at the width of the screen
+func (b *browser) truncate(line string) string {
+	if runes := []rune(line); len(runes) > b.width {
+		return string(runes[:b.width])
+	}
+
+	return line
+}
+
+// formatTable aligns the cells in columns, like a tab writer
+func formatTable(cells [][]string) []string {
+	widths := make([]int, len(columns))
+	for _, row := range cells {
+		for i, cell := range row {
+			if width := len([]rune(cell)); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(cells))
+	for _, row := range cells {
+		var line strings.Builder
+		line.WriteString(" ")
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-len([]rune(cell))+columnSpacing))
+			}
+		}
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// conditionStatus returns a function that returns the status of the condition of a managed cluster
+func conditionStatus(conditionType string) func(obj *unstructured.Unstructured) string {
+	return func(obj *unstructured.Unstructured) string {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, condition := range conditions {
+			if conditionMap, ok := condition.(map[string]interface{}); ok && conditionMap["type"] == conditionType {
+				return pluginutil.ValueOrNone(fmt.Sprint(conditionMap["status"]))
+			}
+		}
+
+		return pluginutil.NoneValue
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package ui
+
+import (
+	"unicode/utf8"
+)
+
+// The special keys, named by their escape sequences or control characters
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdown"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyInterrupt = "ctrl-c"
+)
+
+const (
+	escapeChar    = 0x1b
+	deleteChar    = 0x7f
+	backspaceChar = 0x08
+	interruptChar = 0x03
+)
+
+// key is a key pressed in the terminal: a printable rune, or else a special key
+type key struct {
+	r       rune
+	special string
+}
+
+// csiKeys are the special keys of the final bytes or the parameters of the escape sequences sent by the
+// terminals in their normal and application cursor modes
+var csiKeys = map[string]string{
+	"A":  keyUp,
+	"B":  keyDown,
+	"C":  keyRight,
+	"D":  keyLeft,
+	"H":  keyHome,
+	"F":  keyEnd,
+	"1~": keyHome,
+	"7~": keyHome,
+	"4~": keyEnd,
+	"8~": keyEnd,
+	"5~": keyPageUp,
+	"6~": keyPageDown,
+}
+
+// parseKeys returns the keys of the input read from a terminal in raw mode. Unknown escape sequences and
+// control characters are ignored.
+func parseKeys(data []byte) []key {
+	var keys []key
+
+	for len(data) > 0 {
+		switch c := data[0]; {
+		case c == escapeChar:
+			special, n := parseEscape(data)
+			if special != "" {
+				keys = append(keys, key{special: special})
+			}
+			data = data[n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{special: keyEnter})
+		case c == deleteChar || c == backspaceChar:
+			keys = append(keys, key{special: keyBackspace})
+		case c == interruptChar:
+			keys = append(keys, key{special: keyInterrupt})
+		case c < ' ':
+		default:
+			r, n := utf8.DecodeRune(data)
+			keys = append(keys, key{r: r})
+			data = data[n:]
+			continue
+		}
+
+		data = data[1:]
+	}
+
+	return keys
+}
+
+// parseEscape returns the special key of the escape sequence at the start of the data and its length. A lone
+// escape character is the escape key.
+func parseEscape(data []byte) (string, int) {
+	if len(data) < 2 || (data[1] != '[' && data[1] != 'O') {
+		return keyEscape, 1
+	}
+
+	for i := 2; i < len(data); i++ {
+		// the final byte of a control sequence
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			return csiKeys[string(data[2:i+1])], i + 1
+		}
+	}
+
+	return "", len(data)
+}
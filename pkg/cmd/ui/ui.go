@@ -0,0 +1,400 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/cmd/describe"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	uiLong = templates.LongDesc(i18n.T(`
+		Browse the managed clusters of the hub of hubs in a full-screen, keyboard-driven terminal UI.
+
+		The managed clusters are refreshed live from the watch stream of Non-K8s API, or polled every
+		--poll-interval if the watch stream is not available. The UI draws with plain ANSI escape
+		sequences, so it runs in any terminal, including over SSH.
+
+		Keys of the list of the managed clusters:
+
+		    up/down, j/k, pgup/pgdown, g/G  move the selection
+		    /                               filter by a part of the name or of a label key=value
+		    1-7, s, S                       sort by a column, the next column, in the reverse order
+		    enter, d                        describe the selected managed cluster
+		    a                               open the actions menu: label or annotate the selected managed cluster
+		    esc                             clear the filter, or go back
+		    q, ctrl-c                       quit
+
+		The label and annotate actions take changes like kubectl label: key=value sets a key and key-
+		removes it, e.g. "environment=prod owner-".`))
+
+	uiExample = templates.Examples(i18n.T(`
+		# Browse all the managed clusters
+		%[1]s ui
+
+		# Browse the production managed clusters
+		%[1]s ui -l environment=prod
+
+		# Browse the managed clusters of the demo fleet
+		%[1]s ui --fake`))
+
+	errNotTerminal = errors.New("the ui command requires a terminal")
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// renderInterval is the interval of redrawing the screen, e.g. to update the ages and to fit a resized terminal
+	renderInterval = time.Second
+
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	enterAlternateScreen = "\x1b[?1049h"
+	exitAlternateScreen  = "\x1b[?1049l"
+	hideCursor           = "\x1b[?25l"
+	showCursor           = "\x1b[?25h"
+)
+
+// Options contains the input to the ui command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	PollInterval  time.Duration
+
+	client      *client.Client
+	eventsPath  string
+	contextName string
+	selector    labels.Selector
+}
+
+// update is an update of the managed clusters from the refresh loop: a list, an event of the watch stream,
+// a change of the source of the updates or an error
+type update struct {
+	objects   []*unstructured.Unstructured
+	eventType watch.EventType
+	object    *unstructured.Unstructured
+	source    string
+	err       error
+}
+
+// NewOptions returns an Options for the ui command, polling every 5 seconds if the watch stream is not
+// available.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	eventsPath string) *Options {
+	return &Options{
+		PollInterval: defaultPollInterval,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		eventsPath:    eventsPath,
+	}
+}
+
+// NewCmd creates a command object for the "ui" action, which browses the managed clusters in a terminal UI.
+// The events path is the path of events in Non-K8s API, described with the managed clusters.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	eventsPath string) *cobra.Command {
+	o := NewOptions(clientFactory, streams, eventsPath)
+
+	cmd := &cobra.Command{
+		Use:                   "ui [-l label]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Browse the managed clusters in a terminal UI"),
+		Long:                  uiLong,
+		Example:               fmt.Sprintf(uiExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) of the managed clusters to browse, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().DurationVar(&o.PollInterval, "poll-interval", o.PollInterval, "The interval between two polls of the managed clusters, if the watch stream is not available, or before watching again if the watch stream ended.")
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector", completion.ManagedClusterLabelSelectors(clientFactory)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	var err error
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.contextName, err = pluginconfig.CurrentContext(o.configFlags)
+	if err != nil {
+		return err
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	if o.PollInterval <= 0 {
+		return cmdutil.UsageErrorf(cmd, "--poll-interval must be positive")
+	}
+
+	return nil
+}
+
+// Run browses the managed clusters until the user quits, restoring the terminal.
+func (o *Options) Run() error {
+	in, isFile := o.In.(*os.File)
+	if !isFile || !term.IsTerminal(int(in.Fd())) {
+		return errNotTerminal
+	}
+
+	out, isFile := o.Out.(*os.File)
+	if !isFile || !term.IsTerminal(int(out.Fd())) {
+		return errNotTerminal
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("unable to set up the terminal: %w", err)
+	}
+	//nolint:errcheck
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, enterAlternateScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+exitAlternateScreen)
+
+	return o.browse(in, out, func() (int, int, error) {
+		return term.GetSize(int(out.Fd()))
+	})
+}
+
+// browse runs the browser with the keys read from the input, drawing the screen of the size to the output,
+// until the user quits or the input ends.
+func (o *Options) browse(in io.Reader, out io.Writer, size func() (int, int, error)) error {
+	objs, err := o.list(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	b := newBrowser(o, o.contextName)
+	b.setObjects(objs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan *update)
+	go o.refresh(ctx, updates)
+
+	keys := make(chan []key)
+	go readKeys(in, keys)
+
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+
+	lastFrame := ""
+
+	for !b.quit {
+		if b.width, b.height, err = size(); err != nil {
+			b.width, b.height = defaultWidth, defaultHeight
+		}
+
+		var frame bytes.Buffer
+		b.render(&frame)
+
+		// the screen is only drawn if it changed, to avoid flickering in slow terminals
+		if frame.String() != lastFrame {
+			if _, err := out.Write(frame.Bytes()); err != nil {
+				return err
+			}
+			lastFrame = frame.String()
+		}
+
+		select {
+		case pressed, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range pressed {
+				b.handleKey(k)
+			}
+		case u := <-updates:
+			b.apply(u)
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// apply applies the update of the refresh loop to the browser
+func (b *browser) apply(u *update) {
+	switch {
+	case u.err != nil:
+		b.message = fmt.Sprintf("unable to refresh the managed clusters: %v", u.err)
+	case u.source != "":
+		b.source = u.source
+	case u.object != nil:
+		b.applyEvent(u.eventType, u.object)
+	default:
+		b.setObjects(u.objects)
+	}
+}
+
+// readKeys sends the keys read from the input, until the input ends
+func readKeys(in io.Reader, keys chan<- []key) {
+	defer close(keys)
+
+	buffer := make([]byte, 256)
+
+	for {
+		n, err := in.Read(buffer)
+		if n > 0 {
+			keys <- parseKeys(buffer[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// refresh sends the updates of the managed clusters from the watch stream, or else from polling, until the
+// context is done. The managed clusters are listed again an interval after a watch stream ends.
+func (o *Options) refresh(ctx context.Context, updates chan<- *update) {
+	send := func(u *update) bool {
+		select {
+		case updates <- u:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	watchSupported := true
+	listed := true
+
+	for {
+		if !listed {
+			objs, err := o.list(ctx)
+			if err != nil {
+				if ctx.Err() != nil || !send(&update{err: err}) {
+					return
+				}
+			} else if !send(&update{objects: objs}) {
+				return
+			}
+		}
+
+		listed = false
+
+		if watchSupported {
+			if !send(&update{source: "watching"}) {
+				return
+			}
+
+			_, err := o.client.Watch(ctx, client.ManagedClustersPath, func(event *client.WatchEvent) (bool, error) {
+				if event.Type != watch.Deleted && !o.selector.Matches(labels.Set(event.Object.GetLabels())) {
+					// a managed cluster relabeled out of the selector is removed from the browser
+					return !send(&update{eventType: watch.Deleted, object: event.Object}), nil
+				}
+				return !send(&update{eventType: event.Type, object: event.Object}), nil
+			})
+			if ctx.Err() != nil {
+				return
+			}
+
+			switch {
+			case err == nil:
+				// the watch stream ended, so the managed clusters are listed again after an interval, not to
+				// overload a server that closes the watch streams immediately
+			case errors.Is(err, client.ErrWatchNotSupported):
+				watchSupported = false
+				if !send(&update{source: fmt.Sprintf("polling every %s", o.PollInterval)}) {
+					return
+				}
+			default:
+				// the watch stream failed, so it is retried after an interval
+				if !send(&update{err: err}) {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.PollInterval):
+		}
+	}
+}
+
+// list returns the managed clusters matching the selector
+func (o *Options) list(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	objs, err := o.client.List(ctx, client.ManagedClustersPath)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		if o.selector.Matches(labels.Set(obj.GetLabels())) {
+			selected = append(selected, obj)
+		}
+	}
+
+	return selected, nil
+}
+
+// describe returns the description of the managed cluster with its events, as printed by the describe command
+func (o *Options) describe(obj *unstructured.Unstructured) (string, error) {
+	// the events are optional, so the managed cluster is described without events if they cannot be read
+	events, err := o.client.List(context.TODO(), o.eventsPath)
+	if err != nil {
+		events = nil
+	}
+
+	var description bytes.Buffer
+	describe.DescribeObject(&description, obj, events)
+
+	return description.String(), nil
+}
+
+// patch applies the merge patch to the managed cluster
+func (o *Options) patch(name string, patch []byte) (*unstructured.Unstructured, error) {
+	return o.client.Patch(context.TODO(), client.ManagedClustersPath, "", name, types.MergePatchType, patch)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/fake"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// browse runs the ui command against the fake Non-K8s API with the keys, and returns the last screen drawn
+func browse(t *testing.T, server *fake.Server, keys string) string {
+	t.Helper()
+
+	c, err := client.New(server.Config())
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	o := NewOptions(client.NewFactory(nil), genericclioptions.NewTestIOStreamsDiscard(), "events")
+	o.client = c
+	o.selector = labels.Everything()
+
+	var out bytes.Buffer
+
+	// the input ends after the keys, which ends the ui command
+	err = o.browse(strings.NewReader(keys), &out, func() (int, int, error) {
+		return 120, 12, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frames := strings.Split(out.String(), cursorHome)
+
+	return frames[len(frames)-1]
+}
+
+func newServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	if err := server.LoadDefaultFixtures(); err != nil {
+		t.Fatalf("unable to load fixtures: %v", err)
+	}
+
+	return server
+}
+
+func TestBrowse(t *testing.T) {
+	server := newServer(t)
+
+	tests := []struct {
+		name     string
+		keys     string
+		expected []string
+		// ordered is true if the expected strings are expected in their order
+		ordered bool
+		hidden  []string
+	}{
+		{name: "list", keys: "", expected: []string{"[4/4]", "AVAILABLE", "cluster1", "cluster4", "q quit"}},
+		{
+			name:     "filter by label",
+			keys:     "/environment=prod\r",
+			expected: []string{"[3/4]", "cluster2"},
+			hidden:   []string{"cluster1"},
+		},
+		{name: "filter cleared", keys: "/prod\x1b", expected: []string{"[4/4]", "cluster1"}},
+		{
+			name:     "sort by version",
+			keys:     "5",
+			expected: []string{"sort: KUBERNETES", " cluster3 ", " cluster1 "},
+			ordered:  true,
+		},
+		{
+			name:     "sort in reverse order",
+			keys:     "55",
+			expected: []string{"sort: KUBERNETES (desc)", " cluster4 ", " cluster3 "},
+			ordered:  true,
+		},
+		{
+			name:     "describe",
+			keys:     "/cluster2\r\r",
+			expected: []string{"Describe managedcluster/cluster2", "Kind:          ManagedCluster", "esc back"},
+		},
+		{
+			name:     "actions menu",
+			keys:     "a",
+			expected: []string{"Actions on managedcluster/cluster1", "Label (l)", "Annotate (a)"},
+		},
+		{name: "invalid change", keys: "alfoo\r", expected: []string{`invalid change, must be key=value or key-: "foo"`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			screen := browse(t, server, test.keys)
+
+			rest := screen
+			for _, expected := range test.expected {
+				index := strings.Index(rest, expected)
+				if index < 0 {
+					t.Errorf("expected %q in screen:\n%s", expected, screen)
+					continue
+				}
+				if test.ordered {
+					rest = rest[index:]
+				}
+			}
+
+			for _, hidden := range test.hidden {
+				if strings.Contains(screen, hidden) {
+					t.Errorf("unexpected %q in screen:\n%s", hidden, screen)
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshWaitsAfterWatchEnds(t *testing.T) {
+	var watches int32
+
+	// a server that ends the watch streams after the first event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := "[]"
+		if r.URL.Query().Get("watch") == "true" {
+			atomic.AddInt32(&watches, 1)
+			body = `{"type":"ADDED","object":{"kind":"ManagedCluster","metadata":{"name":"cluster1"}}}`
+		}
+		//nolint:errcheck
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(&client.Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	o := NewOptions(client.NewFactory(nil), genericclioptions.NewTestIOStreamsDiscard(), "events")
+	o.client = c
+	o.selector = labels.Everything()
+	o.PollInterval = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	updates := make(chan *update)
+	go func() {
+		for range updates {
+		}
+	}()
+
+	o.refresh(ctx, updates)
+	close(updates)
+
+	if n := atomic.LoadInt32(&watches); n < 1 || n > 3 {
+		t.Errorf("unexpected number of watches: %d", n)
+	}
+}
+
+func TestLabelAndAnnotate(t *testing.T) {
+	server := newServer(t)
+
+	screen := browse(t, server, "/cluster3\ral tier=gold environment-\r")
+	if !strings.Contains(screen, "managedcluster/cluster3 labeled") {
+		t.Errorf("expected the managed cluster labeled in screen:\n%s", screen)
+	}
+
+	browse(t, server, "/cluster3\raa owner=sre\r")
+
+	for _, obj := range server.Objects(client.ManagedClustersPath) {
+		if obj.GetName() != "cluster3" {
+			continue
+		}
+
+		if obj.GetLabels()["tier"] != "gold" {
+			t.Errorf("expected label tier=gold, got %v", obj.GetLabels())
+		}
+
+		if _, found := obj.GetLabels()["environment"]; found {
+			t.Errorf("expected label environment removed, got %v", obj.GetLabels())
+		}
+
+		if obj.GetAnnotations()["owner"] != "sre" {
+			t.Errorf("expected annotation owner=sre, got %v", obj.GetAnnotations())
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("a\x1b[A\x1b[6~\r\x7f\x1b\x03é"))
+
+	expected := []key{
+		{r: 'a'}, {special: keyUp}, {special: keyPageDown}, {special: keyEnter}, {special: keyBackspace},
+		{special: keyEscape}, {special: keyInterrupt}, {r: 'é'},
+	}
+
+	if len(keys) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("expected %v at %d, got %v", expected[i], i, keys[i])
+		}
+	}
+}
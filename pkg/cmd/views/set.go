@@ -0,0 +1,189 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+var (
+	errEmptyView     = errors.New("at least one of --columns, --selector, --sort-by or --group-by must be specified")
+	errInvalidColumn = errors.New("invalid column, must be NAME:JSONPATH")
+)
+
+// SetOptions holds the options of the views set command
+type SetOptions struct {
+	genericclioptions.IOStreams
+
+	Columns string
+	View    pluginconfig.View
+
+	path string
+	name string
+}
+
+// NewSetOptions returns initialized SetOptions.
+func NewSetOptions(streams genericclioptions.IOStreams) *SetOptions {
+	return &SetOptions{
+		IOStreams: streams,
+	}
+}
+
+// NewCmdSet creates a command object for the "views set" action, which saves a view in the config file.
+func NewCmdSet(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewSetOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:                   "set NAME [--columns=NAME:JSONPATH,...] [-l selector] [--sort-by=JSONPATH] [--group-by=GROUP]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Save a view in the config file, replacing the view with the same name"),
+		ValidArgsFunction:     viewNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Columns, "columns", o.Columns, "The columns of the view, comma separated, as NAME:JSONPATH (e.g. NAME:.metadata.name,VERSION:.status.version.kubernetes), like the custom-columns output format.")
+	cmd.Flags().StringVarP(&o.View.Selector, "selector", "l", o.View.Selector, "The label selector of the managed clusters of the view (e.g. -l key1=value1,key2=value2).")
+	cmd.Flags().StringVar(&o.View.SortBy, "sort-by", o.View.SortBy, "The JSONPath expression the managed clusters of the view are sorted by (e.g. '{.metadata.name}').")
+	cmd.Flags().StringVar(&o.View.GroupBy, "group-by", o.View.GroupBy, "The grouping of the managed clusters of the view: 'hub', a JSONPath expression or a label key.")
+	cmd.Flags().StringVar(&o.View.Description, "description", o.View.Description, "The description of the view in the list of the views.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *SetOptions) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one name of a view must be specified, got: %v", args)
+	}
+
+	o.name = args[0]
+	o.path = pluginconfig.Path()
+
+	if o.Columns != "" {
+		o.View.Columns = strings.Split(o.Columns, ",")
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *SetOptions) Validate() error {
+	if len(o.View.Columns) == 0 && o.View.Selector == "" && o.View.SortBy == "" && o.View.GroupBy == "" {
+		return errEmptyView
+	}
+
+	for _, column := range o.View.Columns {
+		if parts := strings.SplitN(column, ":", 2); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("%w: %q", errInvalidColumn, column)
+		}
+	}
+
+	return nil
+}
+
+// Run saves the view in the config file.
+func (o *SetOptions) Run() error {
+	config, err := pluginconfig.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	view := o.View
+	config.SetView(o.name, &view)
+
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "View %q saved.\n", o.name)
+
+	return nil
+}
+
+// DeleteOptions holds the options of the views delete command
+type DeleteOptions struct {
+	genericclioptions.IOStreams
+
+	path string
+	name string
+}
+
+// NewDeleteOptions returns initialized DeleteOptions.
+func NewDeleteOptions(streams genericclioptions.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		IOStreams: streams,
+	}
+}
+
+// NewCmdDelete creates a command object for the "views delete" action, which deletes a view from the config file.
+func NewCmdDelete(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewDeleteOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:                   "delete NAME",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Delete a view from the config file"),
+		ValidArgsFunction:     viewNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *DeleteOptions) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one name of a view must be specified, got: %v", args)
+	}
+
+	o.name = args[0]
+	o.path = pluginconfig.Path()
+
+	return nil
+}
+
+// Run deletes the view from the config file.
+func (o *DeleteOptions) Run() error {
+	config, err := pluginconfig.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	if err := config.DeleteView(o.name); err != nil {
+		return err
+	}
+
+	if err := config.Save(o.path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "View %q deleted.\n", o.name)
+
+	return nil
+}
+
+// viewNames completes the first argument with the names of the views
+func viewNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completion.ViewNames()(cmd, args, toComplete)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package views
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	viewsLong = templates.LongDesc(i18n.T(`
+		List the views of the get command.
+
+		A view is a named combination of the columns, the label selector, the sort and the grouping of the
+		managed clusters, applied by "get --view=NAME". The flags specified on the command line take precedence
+		over the view, e.g. "get --view=health -l environment=prod".
+
+		The built-in views are health, versions and capacity. The views saved in the config file by
+		"views set" are listed in addition to the built-in views, and replace the built-in views with the
+		same name.`))
+
+	viewsExample = templates.Examples(i18n.T(`
+		# List the views
+		%[1]s views
+
+		# List the views with their columns, selector, sort and grouping
+		%[1]s views -o wide
+
+		# Save a view 'upgrades' of the production managed clusters grouped by Kubernetes version
+		%[1]s views set upgrades -l environment=prod --group-by='{.status.version.kubernetes}' \
+		    --columns='NAME:.metadata.name,VERSION:.status.version.kubernetes'
+
+		# List the managed clusters by the view 'upgrades'
+		%[1]s get --view=upgrades
+
+		# Delete the view 'upgrades' from the config file
+		%[1]s views delete upgrades`))
+
+	errInvalidOutput = errors.New("invalid output format")
+)
+
+const (
+	wideOutput = "wide"
+	yamlOutput = "yaml"
+	nameOutput = "name"
+
+	builtinSource = "builtin"
+	configSource  = "config"
+)
+
+// Options contains the input to the views command.
+type Options struct {
+	genericclioptions.IOStreams
+
+	Output    string
+	NoHeaders bool
+
+	path string
+}
+
+// NewOptions returns initialized Options.
+func NewOptions(streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: streams,
+	}
+}
+
+// NewCmd creates a command object for the "views" action, which lists the views of the get command and
+// manages the views of the config file.
+func NewCmd(parent string, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:                   "views [-o wide|yaml|name]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("List the views of the get command"),
+		Long:                  viewsLong,
+		Example:               fmt.Sprintf(viewsExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When using the default or wide output format, don't print headers (default print headers).")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: wide|yaml|name.")
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Values(wideOutput, yamlOutput, nameOutput)))
+
+	cmd.AddCommand(NewCmdSet(streams))
+	cmd.AddCommand(NewCmdDelete(streams))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	o.path = pluginconfig.Path()
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	switch o.Output {
+	case "", wideOutput, yamlOutput, nameOutput:
+	default:
+		return fmt.Errorf("%w: %q, must be one of wide|yaml|name", errInvalidOutput, o.Output)
+	}
+
+	return nil
+}
+
+// Run lists the views of the config file and the built-in views.
+func (o *Options) Run() error {
+	config, err := pluginconfig.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	switch o.Output {
+	case nameOutput:
+		for _, name := range config.ViewNames() {
+			fmt.Fprintln(o.Out, name)
+		}
+		return nil
+	case yamlOutput:
+		views := map[string]*pluginconfig.View{}
+		for _, name := range config.ViewNames() {
+			views[name], _ = config.View(name)
+		}
+
+		data, err := yaml.Marshal(views)
+		if err != nil {
+			return fmt.Errorf("unable to encode the views: %w", err)
+		}
+
+		_, err = o.Out.Write(data)
+
+		return err
+	}
+
+	o.print(o.Out, config)
+
+	return nil
+}
+
+func (o *Options) print(out io.Writer, config *pluginconfig.Config) {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	if !o.NoHeaders {
+		headers := []string{"NAME", "SOURCE", "DESCRIPTION"}
+		if o.Output == wideOutput {
+			headers = append(headers, "SELECTOR", "SORT-BY", "GROUP-BY", "COLUMNS")
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	for _, name := range config.ViewNames() {
+		view, _ := config.View(name)
+
+		source := configSource
+		if config.IsBuiltinView(name) {
+			source = builtinSource
+		}
+
+		cells := []string{name, source, pluginutil.ValueOrNone(view.Description)}
+		if o.Output == wideOutput {
+			cells = append(cells, pluginutil.ValueOrNone(view.Selector), pluginutil.ValueOrNone(view.SortBy),
+				pluginutil.ValueOrNone(view.GroupBy), pluginutil.ValueOrNone(columnNames(view.Columns)))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+}
+
+// columnNames returns the names of the columns of a view, comma separated
+func columnNames(columns []string) string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, strings.SplitN(column, ":", 2)[0])
+	}
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,433 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/completion"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/jsonpath"
+	kubectlget "k8s.io/kubectl/pkg/cmd/get"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	waitLong = templates.LongDesc(i18n.T(`
+		Wait for a specific condition on one or many resources.
+
+		The command takes a resource type and the names of the resources, or a label selector (-l)
+		or --all to wait for all the matching resources. If no resource type is specified, it waits
+		for managed clusters. A resource that is specified by its name and does not exist yet is
+		waited for until it is created.
+
+		--for=delete waits for the resources to be deleted.
+		--for=condition=TYPE[=STATUS] waits for the status of the condition of the type to be
+		STATUS (True by default).
+		--for=jsonpath='{JSONPath expression}'=VALUE waits for the result of the JSONPath
+		expression to be VALUE.
+
+		The watch stream of Non-K8s API is used if it is available, otherwise the resources
+		are polled every --poll-interval. A --timeout of zero checks the condition once,
+		a negative --timeout waits for a week.`))
+
+	waitExample = templates.Examples(i18n.T(`
+		# Wait for the managed cluster 'mycluster' to become available
+		%[1]s wait managedcluster mycluster --for=condition=ManagedClusterConditionAvailable
+
+		# Wait up to 10 minutes for all the managed clusters labeled environment=dev to be joined
+		%[1]s wait -l environment=dev --for=condition=ManagedClusterJoined --timeout=10m
+
+		# Wait for the Kubernetes version of the managed cluster 'mycluster' to be v1.23.5
+		%[1]s wait managedcluster mycluster --for=jsonpath='{.status.version.kubernetes}'=v1.23.5
+
+		# Wait for the managed cluster 'mycluster' to be deleted
+		%[1]s wait managedcluster mycluster --for=delete --timeout=5m
+
+		# Wait for the policy 'mypolicy' to be compliant
+		%[1]s wait policy mypolicy --for=jsonpath='{.status.compliant}'=Compliant`))
+
+	errInvalidFor          = errors.New("invalid --for")
+	errNoMatchingResources = errors.New("no matching resources found")
+	errTimedOut            = errors.New("timed out waiting for the condition")
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultPollInterval = 5 * time.Second
+	foreverTimeout      = 7 * 24 * time.Hour
+
+	forDelete    = "delete"
+	forCondition = "condition="
+	forJSONPath  = "jsonpath="
+)
+
+// Options contains the input to the wait command.
+type Options struct {
+	genericclioptions.IOStreams
+	configFlags   *genericclioptions.ConfigFlags
+	clientFactory *client.Factory
+
+	LabelSelector string
+	All           bool
+	ForCondition  string
+	Timeout       time.Duration
+	PollInterval  time.Duration
+
+	client    *client.Client
+	registry  *registry.Registry
+	resource  *registry.Resource
+	names     []string
+	namespace string
+	selector  labels.Selector
+	forDelete bool
+	isMet     func(*unstructured.Unstructured) bool
+	met       string
+
+	// targets are the names of the objects to wait for, met are the names of the objects that met the condition
+	targets sets.String
+	metSet  sets.String
+}
+
+// NewOptions returns an Options for the wait command.
+func NewOptions(clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *Options {
+	return &Options{
+		Timeout:      defaultTimeout,
+		PollInterval: defaultPollInterval,
+
+		configFlags:   clientFactory.ConfigFlags(),
+		clientFactory: clientFactory,
+		IOStreams:     streams,
+		registry:      resourceRegistry,
+	}
+}
+
+// NewCmd creates a command object for the "wait" action, which waits for a condition on resources.
+// The default resource of the registry is used if no resource type is specified.
+func NewCmd(parent string, clientFactory *client.Factory, streams genericclioptions.IOStreams,
+	resourceRegistry *registry.Registry) *cobra.Command {
+	o := NewOptions(clientFactory, streams, resourceRegistry)
+
+	cmd := &cobra.Command{
+		Use: "wait ([TYPE] -l label | [TYPE] --all | TYPE NAME...) " +
+			"--for=delete|condition=TYPE[=STATUS]|jsonpath='{...}'=VALUE [--timeout DURATION]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Wait for a specific condition on one or many resources"),
+		Long:                  waitLong,
+		Example:               fmt.Sprintf(waitExample, parent),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Select all the resources of the type.")
+	cmd.Flags().StringVar(&o.ForCondition, "for", o.ForCondition, "The condition to wait on: [delete|condition=condition-name[=condition-value]|jsonpath='{JSONPath expression}'=JSONPath value].")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait before giving up. Zero means check once and don't wait, negative means wait for a week.")
+	cmd.Flags().DurationVar(&o.PollInterval, "poll-interval", o.PollInterval, "The interval between two polls of the resources, if the watch stream is not available, or before watching again if the watch stream ended.")
+
+	cmd.ValidArgsFunction = completion.ResourceTypesAndNames(clientFactory, resourceRegistry, registry.VerbList, true)
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("selector",
+		completion.LabelSelectors(clientFactory, resourceRegistry, registry.VerbList)))
+
+	return cmd
+}
+
+// Complete takes the command arguments and infers any remaining options.
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.resource, o.names, err = o.registry.ResourceFromArgs(args, registry.VerbList)
+	if err != nil {
+		return err
+	}
+
+	o.selector, err = labels.Parse(o.LabelSelector)
+	if err != nil {
+		return err
+	}
+
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if o.Timeout < 0 {
+		o.Timeout = foreverTimeout
+	}
+
+	if err := o.parseFor(); err != nil {
+		return err
+	}
+
+	o.client, err = o.clientFactory.NewClient()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate(cmd *cobra.Command) error {
+	selected := 0
+	for _, isSet := range []bool{len(o.names) > 0, o.LabelSelector != "", o.All} {
+		if isSet {
+			selected++
+		}
+	}
+
+	if selected != 1 {
+		return cmdutil.UsageErrorf(cmd, "exactly one of NAME, -l or --all must be specified")
+	}
+
+	if o.PollInterval <= 0 {
+		return cmdutil.UsageErrorf(cmd, "--poll-interval must be positive")
+	}
+
+	return nil
+}
+
+// parseFor parses --for into the function that checks if an object meets the condition
+func (o *Options) parseFor() error {
+	switch {
+	case o.ForCondition == "":
+		return fmt.Errorf("%w: --for must be specified", errInvalidFor)
+	case strings.EqualFold(o.ForCondition, forDelete):
+		o.forDelete, o.met = true, "deleted"
+		return nil
+	case strings.HasPrefix(o.ForCondition, forCondition):
+		conditionType := strings.TrimPrefix(o.ForCondition, forCondition)
+		conditionStatus := "True"
+		if i := strings.Index(conditionType, "="); i >= 0 {
+			conditionType, conditionStatus = conditionType[:i], conditionType[i+1:]
+		}
+		if conditionType == "" {
+			return fmt.Errorf("%w: %q, a condition type must be specified", errInvalidFor, o.ForCondition)
+		}
+
+		o.isMet = conditionFunc(conditionType, conditionStatus)
+		o.met = "condition met"
+
+		return nil
+	case strings.HasPrefix(o.ForCondition, forJSONPath):
+		isMet, err := jsonPathFunc(strings.TrimPrefix(o.ForCondition, forJSONPath))
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", errInvalidFor, o.ForCondition, err)
+		}
+
+		o.isMet, o.met = isMet, "condition met"
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, must be one of delete, condition=... or jsonpath=...", errInvalidFor,
+			o.ForCondition)
+	}
+}
+
+// conditionFunc returns a function that checks if the status of the condition of the type of an object is the status
+func conditionFunc(conditionType, conditionStatus string) func(*unstructured.Unstructured) bool {
+	return func(obj *unstructured.Unstructured) bool {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if strings.EqualFold(fmt.Sprint(conditionMap["type"]), conditionType) {
+				return strings.EqualFold(fmt.Sprint(conditionMap["status"]), conditionStatus)
+			}
+		}
+
+		return false
+	}
+}
+
+// jsonPathFunc returns a function that checks if the result of the JSONPath expression of an object is the value,
+// parsing an argument of the form {expression}=value
+func jsonPathFunc(argument string) (func(*unstructured.Unstructured) bool, error) {
+	i := strings.LastIndex(argument, "}=")
+	if !strings.HasPrefix(argument, "{") || i < 0 {
+		return nil, fmt.Errorf("the argument must be of the form '{JSONPath expression}'=value")
+	}
+
+	expression, err := kubectlget.RelaxedJSONPathExpression(argument[:i+1])
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, err
+	}
+
+	value := argument[i+2:]
+
+	return func(obj *unstructured.Unstructured) bool {
+		results, err := parser.FindResults(obj.Object)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			return false
+		}
+
+		return fmt.Sprint(results[0][0].Interface()) == value
+	}, nil
+}
+
+// Run performs the wait operation.
+func (o *Options) Run() error {
+	o.metSet = sets.NewString()
+
+	if o.Timeout == 0 {
+		objects, err := o.list(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		if done, err := o.evaluate(objects); done || err != nil {
+			return err
+		}
+
+		return o.timeoutError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+	defer cancel()
+
+	watchSupported := true
+
+	for {
+		objects, err := o.list(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return o.timeoutError()
+			}
+			return err
+		}
+
+		done, err := o.evaluate(objects)
+		if done || err != nil {
+			return err
+		}
+
+		if watchSupported {
+			done, err = o.watch(ctx, objects)
+			if done {
+				return nil
+			}
+			switch {
+			case errors.Is(err, client.ErrWatchNotSupported):
+				watchSupported = false
+			case ctx.Err() != nil:
+				return o.timeoutError()
+			case err != nil && !client.IsRetryable(err):
+				return err
+			}
+		}
+
+		// the watch stream ended or failed with a retryable error, or is not available, so the state is listed
+		// again after an interval, not to overload a server that closes the watch streams immediately
+		select {
+		case <-ctx.Done():
+			return o.timeoutError()
+		case <-time.After(o.PollInterval):
+		}
+	}
+}
+
+// list returns the objects by name, of the namespace if the resource is namespaced
+func (o *Options) list(ctx context.Context) (map[string]*unstructured.Unstructured, error) {
+	objs, err := o.client.List(ctx, o.resource.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		if o.inNamespace(obj) {
+			objects[obj.GetName()] = obj
+		}
+	}
+
+	return objects, nil
+}
+
+// watch updates the objects by the events of the watch stream until the condition is met on all the targets
+func (o *Options) watch(ctx context.Context, objects map[string]*unstructured.Unstructured) (bool, error) {
+	return o.client.Watch(ctx, o.resource.Path,
+		func(event *client.WatchEvent) (bool, error) {
+			if !o.inNamespace(event.Object) {
+				return false, nil
+			}
+			if event.Type == watch.Deleted {
+				delete(objects, event.Object.GetName())
+			} else {
+				objects[event.Object.GetName()] = event.Object
+			}
+
+			return o.evaluate(objects)
+		})
+}
+
+// inNamespace returns true if the resource is not namespaced or the object is in the namespace
+func (o *Options) inNamespace(obj *unstructured.Unstructured) bool {
+	return !o.resource.Namespaced() || obj.GetNamespace() == o.namespace
+}
+
+// evaluate prints the targets that newly met the condition and returns true if all the targets met it.
+// The targets are the specified names, or else the objects that are selected when first evaluated.
+func (o *Options) evaluate(objects map[string]*unstructured.Unstructured) (bool, error) {
+	if o.targets == nil {
+		o.targets = sets.NewString(o.names...)
+		if len(o.names) == 0 {
+			for name, obj := range objects {
+				if o.All || o.selector.Matches(labels.Set(obj.GetLabels())) {
+					o.targets.Insert(name)
+				}
+			}
+		}
+
+		if o.targets.Len() == 0 {
+			return false, errNoMatchingResources
+		}
+	}
+
+	for _, name := range o.targets.List() {
+		if o.metSet.Has(name) {
+			continue
+		}
+
+		obj, found := objects[name]
+		if (o.forDelete && !found) || (!o.forDelete && found && o.isMet(obj)) {
+			o.metSet.Insert(name)
+			fmt.Fprintf(o.Out, "%s/%s %s\n", o.resource.Name(), name, o.met)
+		}
+	}
+
+	return o.metSet.Len() == o.targets.Len(), nil
+}
+
+func (o *Options) timeoutError() error {
+	pending := o.targets.Difference(o.metSet).List()
+	if len(pending) == 0 {
+		return errTimedOut
+	}
+
+	return fmt.Errorf("%w on %s/%s", errTimedOut, o.resource.Name(), strings.Join(pending, ", "))
+}
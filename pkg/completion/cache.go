@@ -0,0 +1,89 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package completion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	dirPermissions  = 0o750
+	filePermissions = 0o600
+)
+
+// unsafeFileNameCharacters are replaced in the names of the cache files
+var unsafeFileNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// cache caches the objects listed for completion in a file per kubeconfig context, URL of Non-K8s API and resource
+// path
+type cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// get returns the objects of the resource path cached for the context and the URL, if not expired
+func (c *cache) get(contextName, url, resourcePath string) ([]*unstructured.Unstructured, bool) {
+	fileName := c.fileName(contextName, url, resourcePath)
+
+	info, err := os.Stat(fileName)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, false
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, false
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		objs = append(objs, &unstructured.Unstructured{Object: item})
+	}
+
+	return objs, true
+}
+
+// set caches the objects of the resource path listed for the context and the URL
+func (c *cache) set(contextName, url, resourcePath string, objs []*unstructured.Unstructured) error {
+	items := make([]map[string]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		items = append(items, obj.Object)
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("unable to encode the objects: %w", err)
+	}
+
+	fileName := c.fileName(contextName, url, resourcePath)
+
+	if err := os.MkdirAll(filepath.Dir(fileName), dirPermissions); err != nil {
+		return fmt.Errorf("unable to create the cache directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(fileName, data, filePermissions); err != nil {
+		return fmt.Errorf("unable to write the cache file: %w", err)
+	}
+
+	return nil
+}
+
+func (c *cache) fileName(contextName, url, resourcePath string) string {
+	return filepath.Join(c.dir, unsafeFileNameCharacters.ReplaceAllString(contextName, "_"),
+		unsafeFileNameCharacters.ReplaceAllString(url, "_"),
+		unsafeFileNameCharacters.ReplaceAllString(resourcePath, "_")+".json")
+}
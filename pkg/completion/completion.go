@@ -0,0 +1,380 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package completion provides the dynamic shell completion of the commands: the names of the objects served by
+// Non-K8s API, of the leaf hubs, the label selectors and the field paths of the objects. The objects are listed
+// from Non-K8s API and cached briefly, since completing a command line may list them several times.
+package completion
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	pluginconfig "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/config"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// CacheTTL is the time the objects listed for completion are cached for
+const CacheTTL = 30 * time.Second
+
+const (
+	// cacheDir is the directory of the completion cache in the cache directory of kubectl
+	cacheDir = "mcl/completion"
+	// listTimeout is the time completion waits for Non-K8s API, so that a slow hub does not block the shell
+	listTimeout = 5 * time.Second
+	// allNamespacesFlag is the flag of the commands listing the objects of all the namespaces
+	allNamespacesFlag = "all-namespaces"
+)
+
+// Func completes the arguments or the value of a flag of a command, as cobra.Command.ValidArgsFunction
+type Func = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// Values completes the values, e.g. the output formats of a command.
+func Values(values ...string) Func {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return withPrefix(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ResourceTypes completes the first argument with the resource types allowing the verb.
+func ResourceTypes(resourceRegistry *registry.Registry, verb string) Func {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return withPrefix(resourceTypes(resourceRegistry, verb), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ResourceTypesAndNames completes the TYPE NAME... arguments of a command: the first argument with the resource
+// types allowing the verb, the next arguments with the names of the objects of the resource that are not specified
+// yet. If multiple is false, a single name is completed.
+func ResourceTypesAndNames(clientFactory *client.Factory, resourceRegistry *registry.Registry,
+	verb string, multiple bool) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return withPrefix(resourceTypes(resourceRegistry, verb), toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		resource, names, err := resourceRegistry.ResourceFromArgs(args, verb)
+		if err != nil || (!multiple && len(names) > 0) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var completions []string
+
+		namespace := ""
+		if resource.Namespaced() && !allNamespaces(cmd) {
+			namespace, _, _ = clientFactory.ConfigFlags().ToRawKubeConfigLoader().Namespace()
+		}
+
+		for _, obj := range list(clientFactory, resource.Path) {
+			if namespace == "" || obj.GetNamespace() == namespace {
+				completions = append(completions, obj.GetName())
+			}
+		}
+
+		return withPrefix(without(completions, names), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ManagedClusterNames completes the arguments with the names of the managed clusters that are not specified yet.
+// If multiple is false, a single name is completed.
+func ManagedClusterNames(clientFactory *client.Factory, multiple bool) Func {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !multiple && len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var names []string
+		for _, obj := range list(clientFactory, client.ManagedClustersPath) {
+			names = append(names, obj.GetName())
+		}
+
+		return withPrefix(without(names, args), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ManagedClusterValues completes the values returned by the function for the managed clusters, e.g. the names of
+// their resources.
+func ManagedClusterValues(clientFactory *client.Factory,
+	values func(obj *unstructured.Unstructured) []string) Func {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions := sets.NewString()
+		for _, obj := range list(clientFactory, client.ManagedClustersPath) {
+			completions.Insert(values(obj)...)
+		}
+
+		completions.Delete("")
+
+		return withPrefix(completions.List(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// HubNames completes the names of the leaf hubs reporting the managed clusters.
+func HubNames(clientFactory *client.Factory) Func {
+	return ManagedClusterValues(clientFactory, func(obj *unstructured.Unstructured) []string {
+		return []string{util.GetLeafHubName(obj)}
+	})
+}
+
+// ViewNames completes the names of the views of the config file and of the built-in views.
+func ViewNames() Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		config, err := pluginconfig.Load(pluginconfig.Path())
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return Values(config.ViewNames()...)(cmd, args, toComplete)
+	}
+}
+
+// ContextNames completes a comma-separated list of the names of the contexts of the kubeconfig, without the
+// contexts already in the list.
+func ContextNames(configFlags *genericclioptions.ConfigFlags) Func {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		kubeconfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		specified := strings.Split(toComplete, ",")
+		specified = specified[:len(specified)-1]
+
+		prefix := ""
+		if len(specified) > 0 {
+			prefix = strings.Join(specified, ",") + ","
+		}
+
+		var contextNames []string
+		for contextName := range kubeconfig.Contexts {
+			contextNames = append(contextNames, contextName)
+		}
+
+		sort.Strings(contextNames)
+
+		var completions []string
+		for _, contextName := range without(contextNames, specified) {
+			completions = append(completions, prefix+contextName)
+		}
+
+		return withPrefix(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// LabelSelectors completes a label selector with the label keys, or the label values of a key followed by an
+// operator, of the objects of the resource specified by the arguments, as resolved for the verb.
+func LabelSelectors(clientFactory *client.Factory, resourceRegistry *registry.Registry,
+	verb string) Func {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		resource, _, err := resourceRegistry.ResourceFromArgs(args, verb)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return labelSelectors(list(clientFactory, resource.Path), toComplete)
+	}
+}
+
+// ManagedClusterLabelSelectors completes a label selector with the label keys, or the label values of a key
+// followed by an operator, of the managed clusters.
+func ManagedClusterLabelSelectors(clientFactory *client.Factory) Func {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return labelSelectors(list(clientFactory, client.ManagedClustersPath), toComplete)
+	}
+}
+
+// SortByFields completes the JSONPath field paths of the scalar fields of the objects of the resource specified by
+// the arguments, e.g. .metadata.name, as accepted by --sort-by. The paths are enclosed in braces if the value to
+// complete starts with a brace.
+func SortByFields(clientFactory *client.Factory, resourceRegistry *registry.Registry) Func {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		resource, _, err := resourceRegistry.ResourceFromArgs(args, registry.VerbList)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		paths := sets.NewString()
+		for _, obj := range list(clientFactory, resource.Path) {
+			addFieldPaths("", obj.Object, paths)
+		}
+
+		completions := paths.List()
+		if strings.HasPrefix(toComplete, "{") {
+			for i := range completions {
+				completions[i] = "{" + completions[i] + "}"
+			}
+		}
+
+		return withPrefix(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// labelSelectors completes the last requirement of the label selector, after its last comma
+func labelSelectors(objs []*unstructured.Unstructured, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix, requirement := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, requirement = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	labels := map[string]sets.String{}
+
+	for _, obj := range objs {
+		for key, value := range obj.GetLabels() {
+			if labels[key] == nil {
+				labels[key] = sets.NewString()
+			}
+			labels[key].Insert(value)
+		}
+	}
+
+	var completions []string
+
+	if i := strings.Index(requirement, "="); i >= 0 {
+		key, operator := requirement[:i], "="
+		if strings.HasSuffix(key, "!") {
+			key, operator = strings.TrimSuffix(key, "!"), "!="
+		} else if strings.HasPrefix(requirement[i+1:], "=") {
+			operator = "=="
+		}
+
+		for _, value := range labels[key].List() {
+			completions = append(completions, prefix+key+operator+value)
+		}
+
+		return withPrefix(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	for key := range labels {
+		completions = append(completions, prefix+key+"=")
+	}
+
+	sort.Strings(completions)
+
+	// the value follows the key, so no space is added after it
+	return withPrefix(completions, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// addFieldPaths adds the JSONPath field paths of the scalar fields of the value to the paths, escaping the dots
+// of the keys. Lists are skipped, since their fields can not be sorted by.
+func addFieldPaths(path string, value interface{}, paths sets.String) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			addFieldPaths(path+"."+strings.ReplaceAll(key, ".", `\.`), field, paths)
+		}
+	case []interface{}, nil:
+	default:
+		paths.Insert(path)
+	}
+}
+
+// list returns the objects of the resource path, from the cache if they were listed recently. Completion does
+// not fail, so the errors are only logged for debugging and no objects are returned.
+func list(clientFactory *client.Factory, resourcePath string) []*unstructured.Unstructured {
+	configFlags := clientFactory.ConfigFlags()
+
+	dir := ""
+	if configFlags.CacheDir != nil {
+		dir = *configFlags.CacheDir
+	}
+
+	contextName, err := pluginconfig.CurrentContext(configFlags)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	apiClient, err := clientFactory.NewClient()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	// the objects of a fake Non-K8s API are not cached
+	var objsCache *cache
+	if !clientFactory.IsFixed() {
+		objsCache = &cache{dir: filepath.Join(dir, cacheDir), ttl: CacheTTL}
+
+		if objs, found := objsCache.get(contextName, apiClient.URL(), resourcePath); found {
+			return objs
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	objs, err := apiClient.List(ctx, resourcePath)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+
+	if objsCache != nil {
+		if err := objsCache.set(contextName, apiClient.URL(), resourcePath, objs); err != nil {
+			cobra.CompDebugln(err.Error(), true)
+		}
+	}
+
+	return objs
+}
+
+// resourceTypes returns the paths of the resources allowing the verb
+func resourceTypes(resourceRegistry *registry.Registry, verb string) []string {
+	var types []string
+
+	for _, resource := range resourceRegistry.Resources() {
+		if resource.Allows(verb) {
+			types = append(types, resource.Path)
+		}
+	}
+
+	return types
+}
+
+// allNamespaces returns true if the command has an --all-namespaces flag and it is set
+func allNamespaces(cmd *cobra.Command) bool {
+	value, err := cmd.Flags().GetBool(allNamespacesFlag)
+	return err == nil && value
+}
+
+// withPrefix returns the completions starting with the prefix
+func withPrefix(completions []string, prefix string) []string {
+	var result []string
+
+	for _, completion := range completions {
+		if strings.HasPrefix(completion, prefix) {
+			result = append(result, completion)
+		}
+	}
+
+	return result
+}
+
+// without returns the completions that are not excluded
+func without(completions, excluded []string) []string {
+	excludedSet := sets.NewString(excluded...)
+
+	var result []string
+
+	for _, completion := range completions {
+		if !excludedSet.Has(completion) {
+			result = append(result, completion)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package config provides the configuration file of the plugin, which holds settings per kubeconfig context, e.g.
+// the URL of Non-K8s API and the default output format of the commands.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// EnvVar is the environment variable holding the path of the config file, instead of the default path
+	EnvVar = "MCL_CONFIG"
+
+	dirPermissions  = 0o750
+	filePermissions = 0o600
+)
+
+// defaultPath is the path of the config file in the home directory
+var defaultPath = filepath.Join(".kube", "mcl", "config.yaml")
+
+// Config is the configuration of the plugin.
+type Config struct {
+	// Contexts holds the settings by the name of their kubeconfig context
+	Contexts map[string]*Settings `json:"contexts,omitempty"`
+	// Views holds the named views of the get command, in addition to the built-in views
+	Views map[string]*View `json:"views,omitempty"`
+}
+
+// Path returns the path of the config file, from the environment variable or else in the home directory.
+func Path() string {
+	if path := os.Getenv(EnvVar); path != "" {
+		return path
+	}
+
+	return filepath.Join(homedir.HomeDir(), defaultPath)
+}
+
+// Load reads the config file at the path. A missing config file is an empty config.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("unable to parse the config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Save writes the config file at the path, creating its directory if needed.
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("unable to encode the config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("unable to create the config directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, filePermissions); err != nil {
+		return fmt.Errorf("unable to write the config file: %w", err)
+	}
+
+	return nil
+}
+
+// SettingsFor returns the settings of the context, empty settings if the context has none.
+func (c *Config) SettingsFor(contextName string) *Settings {
+	if settings, found := c.Contexts[contextName]; found && settings != nil {
+		return settings
+	}
+
+	return &Settings{}
+}
+
+// SetSettings sets the settings of the context, removing the context if its settings are empty.
+func (c *Config) SetSettings(contextName string, settings *Settings) {
+	if settings.IsEmpty() {
+		delete(c.Contexts, contextName)
+		return
+	}
+
+	if c.Contexts == nil {
+		c.Contexts = map[string]*Settings{}
+	}
+
+	c.Contexts[contextName] = settings
+}
+
+// CurrentContext returns the name of the kubeconfig context the commands run against: the context of the
+// --context flag, or else the current context of the kubeconfig.
+func CurrentContext(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		return *configFlags.Context, nil
+	}
+
+	kubeconfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return kubeconfig.CurrentContext, nil
+}
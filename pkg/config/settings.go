@@ -0,0 +1,256 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The keys of the settings, as used by the config command
+const (
+	URLKey          = "url"
+	CAFileKey       = "caFile"
+	InsecureKey     = "insecureSkipTLSVerify"
+	OutputKey       = "output"
+	SelectorKey     = "selector"
+	ColumnsKey      = "columns"
+	TimeoutKey      = "timeout"
+	RetriesKey      = "retries"
+	RetryBackoffKey = "retryBackoff"
+)
+
+const (
+	// settingAnnotation is the annotation of the flags defaulted by a setting, holding the key of the setting
+	settingAnnotation = "mcl.open-cluster-management.io/setting"
+	// appliedAnnotation is the annotation of the flags set to the value of their setting by ApplyFlagDefaults
+	appliedAnnotation = "mcl.open-cluster-management.io/applied"
+)
+
+var (
+	errUnknownKey   = errors.New("unknown setting")
+	errInvalidValue = errors.New("invalid value")
+)
+
+// Settings are the settings of a kubeconfig context. The settings of the flags are defaults, applied unless the
+// flags are specified.
+type Settings struct {
+	// URL is the URL of Non-K8s API, instead of the URL derived from the API server of the hub of hubs
+	URL string `json:"url,omitempty"`
+	// CAFile is the file of the certificate authorities verifying the certificate of Non-K8s API. If empty, the
+	// certificate authority of the cluster of the context in the kubeconfig is used.
+	CAFile string `json:"caFile,omitempty"`
+	// InsecureSkipTLSVerify disables the verification of the certificate of Non-K8s API
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+	// Output is the default output format of the get command
+	Output string `json:"output,omitempty"`
+	// Selector is the default label selector of the commands listing managed clusters
+	Selector string `json:"selector,omitempty"`
+	// Columns are the default labels of the get command presented as columns
+	Columns []string `json:"columns,omitempty"`
+	// Timeout is the time to wait for the responses of Non-K8s API, no limit if nil
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Retries is the number of times the requests reading from Non-K8s API are retried on transient failures
+	Retries int `json:"retries,omitempty"`
+	// RetryBackoff is the delay before the first retry, doubled for each next retry
+	RetryBackoff *metav1.Duration `json:"retryBackoff,omitempty"`
+}
+
+// setting gets and sets a setting as a string
+type setting struct {
+	get func(s *Settings) string
+	set func(s *Settings, value string) error
+}
+
+// settings are the settings by key
+var settings = map[string]setting{
+	URLKey: {
+		get: func(s *Settings) string { return s.URL },
+		set: func(s *Settings, value string) error { s.URL = value; return nil },
+	},
+	CAFileKey: {
+		get: func(s *Settings) string { return s.CAFile },
+		set: func(s *Settings, value string) error { s.CAFile = value; return nil },
+	},
+	InsecureKey: {
+		get: func(s *Settings) string {
+			if !s.InsecureSkipTLSVerify {
+				return ""
+			}
+			return strconv.FormatBool(s.InsecureSkipTLSVerify)
+		},
+		set: func(s *Settings, value string) error {
+			if value == "" {
+				s.InsecureSkipTLSVerify = false
+				return nil
+			}
+			insecure, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%w: %q, must be true or false", errInvalidValue, value)
+			}
+			s.InsecureSkipTLSVerify = insecure
+			return nil
+		},
+	},
+	OutputKey: {
+		get: func(s *Settings) string { return s.Output },
+		set: func(s *Settings, value string) error { s.Output = value; return nil },
+	},
+	SelectorKey: {
+		get: func(s *Settings) string { return s.Selector },
+		set: func(s *Settings, value string) error { s.Selector = value; return nil },
+	},
+	ColumnsKey: {
+		get: func(s *Settings) string { return strings.Join(s.Columns, ",") },
+		set: func(s *Settings, value string) error {
+			s.Columns = nil
+			if value != "" {
+				s.Columns = strings.Split(value, ",")
+			}
+			return nil
+		},
+	},
+	TimeoutKey: {
+		get: func(s *Settings) string { return formatDuration(s.Timeout) },
+		set: func(s *Settings, value string) error { return parseDuration(value, &s.Timeout) },
+	},
+	RetriesKey: {
+		get: func(s *Settings) string {
+			if s.Retries == 0 {
+				return ""
+			}
+			return strconv.Itoa(s.Retries)
+		},
+		set: func(s *Settings, value string) error {
+			if value == "" {
+				s.Retries = 0
+				return nil
+			}
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return fmt.Errorf("%w: %q, must be a non-negative integer", errInvalidValue, value)
+			}
+			s.Retries = retries
+			return nil
+		},
+	},
+	RetryBackoffKey: {
+		get: func(s *Settings) string { return formatDuration(s.RetryBackoff) },
+		set: func(s *Settings, value string) error { return parseDuration(value, &s.RetryBackoff) },
+	},
+}
+
+// Keys returns the keys of the settings, sorted.
+func Keys() []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Get returns the value of the setting as a string, empty if not set.
+func (s *Settings) Get(key string) (string, error) {
+	setting, found := settings[key]
+	if !found {
+		return "", fmt.Errorf("%w: %q, must be one of %s", errUnknownKey, key, strings.Join(Keys(), "|"))
+	}
+
+	return setting.get(s), nil
+}
+
+// Set parses the value of the setting from a string. An empty value unsets the setting.
+func (s *Settings) Set(key, value string) error {
+	setting, found := settings[key]
+	if !found {
+		return fmt.Errorf("%w: %q, must be one of %s", errUnknownKey, key, strings.Join(Keys(), "|"))
+	}
+
+	return setting.set(s, value)
+}
+
+// IsEmpty returns true if no setting is set.
+func (s *Settings) IsEmpty() bool {
+	for _, setting := range settings {
+		if setting.get(s) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MarkFlagSetting makes the setting the default of the flag, applied by ApplyFlagDefaults.
+func MarkFlagSetting(flags *pflag.FlagSet, name, key string) error {
+	return flags.SetAnnotation(name, settingAnnotation, []string{key})
+}
+
+// ApplyFlagDefaults sets the flags marked by MarkFlagSetting that are not specified to the values of their
+// settings, so that the commands complete their options as if the flags were specified.
+func (s *Settings) ApplyFlagDefaults(flags *pflag.FlagSet) error {
+	var err error
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		keys := flag.Annotations[settingAnnotation]
+		if err != nil || len(keys) == 0 || flag.Changed {
+			return
+		}
+
+		value, getErr := s.Get(keys[0])
+		if getErr != nil || value == "" {
+			err = getErr
+			return
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid %s setting %q for --%s: %w", keys[0], value, flag.Name, setErr)
+			return
+		}
+
+		err = flags.SetAnnotation(flag.Name, appliedAnnotation, []string{keys[0]})
+	})
+
+	return err
+}
+
+// IsFlagFromSetting returns true if the flag was set to the value of its setting by ApplyFlagDefaults, rather than
+// specified on the command line or by a view.
+func IsFlagFromSetting(flags *pflag.FlagSet, name string) bool {
+	flag := flags.Lookup(name)
+	return flag != nil && len(flag.Annotations[appliedAnnotation]) > 0
+}
+
+func formatDuration(duration *metav1.Duration) string {
+	if duration == nil {
+		return ""
+	}
+
+	return duration.Duration.String()
+}
+
+func parseDuration(value string, duration **metav1.Duration) error {
+	if value == "" {
+		*duration = nil
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%w: %q, must be a non-negative duration, e.g. 30s", errInvalidValue, value)
+	}
+
+	*duration = &metav1.Duration{Duration: parsed}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ViewFlag is the flag of the commands printing the objects by a view
+const ViewFlag = "view"
+
+// The flags set by the views
+const (
+	outputFlag   = "output"
+	selectorFlag = "selector"
+	sortByFlag   = "sort-by"
+	groupByFlag  = "group-by"
+)
+
+// customColumnsOutput is the output format printing the columns of a view
+const customColumnsOutput = "custom-columns="
+
+// managedByColumn is the column of the leaf hub of the managed clusters in the built-in views
+const managedByColumn = `HUB:.metadata.annotations.hub-of-hubs\.open-cluster-management\.io/managed-by`
+
+var (
+	errUnknownView = errors.New("unknown view")
+	errBuiltinView = errors.New("built-in views can not be deleted")
+)
+
+// View is a named combination of the columns, the selector, the sort and the grouping of the get command.
+type View struct {
+	// Description describes the view in the list of the views
+	Description string `json:"description,omitempty"`
+	// Columns are the columns printed by the view, as NAME:JSONPATH, e.g. NAME:.metadata.name
+	Columns []string `json:"columns,omitempty"`
+	// Selector is the label selector of the objects
+	Selector string `json:"selector,omitempty"`
+	// SortBy is the JSONPath expression the objects are sorted by
+	SortBy string `json:"sortBy,omitempty"`
+	// GroupBy is the grouping of the objects, as by the --group-by flag of the get command
+	GroupBy string `json:"groupBy,omitempty"`
+}
+
+// builtinViews are the views of the managed clusters available without a config file
+var builtinViews = map[string]*View{
+	"health": {
+		Description: "The health of the managed clusters by leaf hub",
+		Columns: []string{
+			"NAME:.metadata.name",
+			`ACCEPTED:.status.conditions[?(@.type=="HubAcceptedManagedCluster")].status`,
+			`JOINED:.status.conditions[?(@.type=="ManagedClusterJoined")].status`,
+			`AVAILABLE:.status.conditions[?(@.type=="ManagedClusterConditionAvailable")].status`,
+			`LAST SYNC:.metadata.annotations.hub-of-hubs\.open-cluster-management\.io/last-status-sync`,
+		},
+		SortBy:  ".metadata.name",
+		GroupBy: "hub",
+	},
+	"versions": {
+		Description: "The managed clusters by Kubernetes version",
+		Columns: []string{
+			"NAME:.metadata.name",
+			managedByColumn,
+			"VENDOR:.metadata.labels.vendor",
+			"KUBERNETES:.status.version.kubernetes",
+		},
+		SortBy:  ".metadata.name",
+		GroupBy: "{.status.version.kubernetes}",
+	},
+	"capacity": {
+		Description: "The capacity and the allocatable resources of the managed clusters",
+		Columns: []string{
+			"NAME:.metadata.name",
+			managedByColumn,
+			"CPU:.status.capacity.cpu",
+			"MEMORY:.status.capacity.memory",
+			"ALLOCATABLE CPU:.status.allocatable.cpu",
+			"ALLOCATABLE MEMORY:.status.allocatable.memory",
+		},
+		SortBy: ".status.capacity.cpu",
+	},
+}
+
+// View returns the view of the config file with the name, or else the built-in view.
+func (c *Config) View(name string) (*View, bool) {
+	if view, found := c.Views[name]; found && view != nil {
+		return view, true
+	}
+
+	view, found := builtinViews[name]
+
+	return view, found
+}
+
+// ViewNames returns the names of the views of the config file and of the built-in views, sorted.
+func (c *Config) ViewNames() []string {
+	names := make([]string, 0, len(c.Views)+len(builtinViews))
+	for name := range builtinViews {
+		names = append(names, name)
+	}
+
+	for name := range c.Views {
+		if _, found := builtinViews[name]; !found {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// IsBuiltinView returns true if the view of the name is a built-in view not replaced by the config file.
+func (c *Config) IsBuiltinView(name string) bool {
+	_, builtin := builtinViews[name]
+	_, configured := c.Views[name]
+
+	return builtin && !configured
+}
+
+// SetView saves the view in the config file, replacing the view with the same name.
+func (c *Config) SetView(name string, view *View) {
+	if c.Views == nil {
+		c.Views = map[string]*View{}
+	}
+
+	c.Views[name] = view
+}
+
+// DeleteView deletes the view from the config file. Built-in views can not be deleted, but the views of the
+// config file replacing them can.
+func (c *Config) DeleteView(name string) error {
+	if _, found := c.Views[name]; found {
+		delete(c.Views, name)
+		return nil
+	}
+
+	if _, found := builtinViews[name]; found {
+		return fmt.Errorf("%w: %s", errBuiltinView, name)
+	}
+
+	return fmt.Errorf("%w: %q", errUnknownView, name)
+}
+
+// ApplyView sets the flags that are not specified to the values of the view of the --view flag, if specified.
+// The view is applied before the settings, so that it takes precedence over them.
+func (c *Config) ApplyView(flags *pflag.FlagSet) error {
+	viewFlag := flags.Lookup(ViewFlag)
+	if viewFlag == nil || viewFlag.Value.String() == "" {
+		return nil
+	}
+
+	name := viewFlag.Value.String()
+
+	view, found := c.View(name)
+	if !found {
+		return fmt.Errorf("%w: %q, must be one of %s", errUnknownView, name, strings.Join(c.ViewNames(), "|"))
+	}
+
+	for flagName, value := range view.flagValues() {
+		flag := flags.Lookup(flagName)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+
+		if err := flags.Set(flagName, value); err != nil {
+			return fmt.Errorf("invalid view %s for --%s: %w", name, flagName, err)
+		}
+	}
+
+	return nil
+}
+
+// flagValues returns the values of the flags set by the view
+func (v *View) flagValues() map[string]string {
+	output := ""
+	if len(v.Columns) > 0 {
+		output = customColumnsOutput + strings.Join(v.Columns, ",")
+	}
+
+	return map[string]string{
+		outputFlag:   output,
+		selectorFlag: v.Selector,
+		sortByFlag:   v.SortBy,
+		groupByFlag:  v.GroupBy,
+	}
+}
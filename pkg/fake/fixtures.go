@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fake
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	fixtureExtension = ".yaml"
+	fixturesDir      = "fixtures"
+)
+
+// fixtures are the default fixtures, a fleet of four managed clusters reported by two leaf hubs
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// LoadFixtures loads the YAML fixtures in the directory of the file system. The resource path of a fixture is
+// its path in the directory without the .yaml extension, e.g. managedclusters.yaml holds the managed clusters
+// and managedclusters/cluster1/kubeconfig.yaml the kubeconfig of cluster1. Fixtures of kind List hold the
+// objects of their resource path, other fixtures are served as they are, as JSON.
+func (s *Server) LoadFixtures(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(filePath) != fixtureExtension {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		body, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return fmt.Errorf("unable to parse fixture %s: %w", filePath, err)
+		}
+
+		resourcePath := strings.TrimSuffix(strings.TrimPrefix(filePath, dir+"/"), fixtureExtension)
+
+		list := &unstructured.UnstructuredList{}
+		if err := list.UnmarshalJSON(body); err != nil || list.GetKind() != "List" {
+			s.SetRaw(resourcePath, body)
+			return nil
+		}
+
+		objs := make([]*unstructured.Unstructured, 0, len(list.Items))
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+
+		s.SetObjects(resourcePath, objs...)
+
+		return nil
+	})
+}
+
+// LoadDefaultFixtures loads the default fixtures, a fleet of four managed clusters reported by two leaf hubs,
+// with their policies, placements, events and kubeconfigs, and the discovery of Non-K8s API.
+func (s *Server) LoadDefaultFixtures() error {
+	return s.LoadFixtures(fixtures, fixturesDir)
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const watchBufferSize = 16
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resourcePath := strings.TrimPrefix(r.URL.Path, apiPath)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:      r.Method,
+		Path:        resourcePath,
+		Query:       r.URL.Query(),
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	injectedError := s.injectedError(r.Method, resourcePath, r.URL.Query().Get("watch") == "true")
+	s.mu.Unlock()
+
+	switch {
+	case !strings.HasPrefix(r.URL.Path, apiPath):
+		http.NotFound(w, r)
+	case r.Header.Get("Authorization") != "Bearer "+Token:
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	case injectedError != nil:
+		http.Error(w, injectedError.Message, injectedError.StatusCode)
+	case r.Method == http.MethodGet && r.URL.Query().Get("watch") == "true":
+		s.serveWatch(w, r, resourcePath)
+	case r.Method == http.MethodGet:
+		s.serveGet(w, r, resourcePath)
+	case r.Method == http.MethodPatch:
+		s.servePatch(w, r, resourcePath, body)
+	case r.Method == http.MethodDelete:
+		s.serveDelete(w, resourcePath)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveGet serves the list of the objects of the resource path as a table if requested by the Accept header
+// and the resource path has table columns, or else as a list. The table or the list is a page if a limit or a
+// continue token is requested or a page size is set, otherwise the list is a JSON array. Resource paths without
+// objects serve their raw body.
+func (s *Server) serveGet(w http.ResponseWriter, r *http.Request, resourcePath string) {
+	s.mu.Lock()
+	objs, found := s.objects[resourcePath]
+	objs = deepCopy(objs)
+	raw, rawFound := s.raw[resourcePath]
+	pageSize := s.pageSize
+	s.mu.Unlock()
+
+	if !found {
+		if !rawFound {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, json.RawMessage(raw))
+		return
+	}
+
+	query := r.URL.Query()
+	columns, table := tableColumns[resourcePath]
+	table = table && strings.Contains(r.Header.Get("Accept"), "as=Table")
+
+	if !table && query.Get("limit") == "" && query.Get("continue") == "" && pageSize == 0 {
+		writeJSON(w, http.StatusOK, toArray(objs))
+		return
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(limit); err != nil || pageSize < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", limit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	start := 0
+	if continueToken := query.Get("continue"); continueToken != "" {
+		var err error
+		if start, err = strconv.Atoi(continueToken); err != nil || start < 0 || start > len(objs) {
+			http.Error(w, fmt.Sprintf("invalid continue token %q", continueToken), http.StatusGone)
+			return
+		}
+	}
+
+	page, continueToken := paginate(objs, start, pageSize)
+
+	if table {
+		pageTable := toTable(columns, page, query.Get("includeObject") == "Object")
+		pageTable.Continue = continueToken
+		writeJSON(w, http.StatusOK, pageTable)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toList(page, continueToken))
+}
+
+// serveWatch streams an added event for each object of the resource path, then the events of the changes of
+// the objects, until the request is canceled or the server is closed.
+func (s *Server) serveWatch(w http.ResponseWriter, r *http.Request, resourcePath string) {
+	s.mu.Lock()
+	objs, found := s.objects[resourcePath]
+	objs = deepCopy(objs)
+	watcher := &watcher{
+		events: make(chan *client.WatchEvent, watchBufferSize),
+		done:   make(chan struct{}),
+	}
+	if found {
+		s.watchers[resourcePath] = append(s.watchers[resourcePath], watcher)
+	}
+	s.mu.Unlock()
+
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer s.removeWatcher(resourcePath, watcher)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	for _, obj := range objs {
+		if err := encoder.Encode(&client.WatchEvent{Type: watch.Added, Object: obj}); err != nil {
+			return
+		}
+	}
+
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case event := <-watcher.events:
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// removeWatcher stops sending events to the watcher
+func (s *Server) removeWatcher(resourcePath string, watcher *watcher) {
+	// unblock the senders before taking the lock they hold
+	close(watcher.done)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watchers := s.watchers[resourcePath]
+	for i := range watchers {
+		if watchers[i] == watcher {
+			s.watchers[resourcePath] = append(watchers[:i:i], watchers[i+1:]...)
+			break
+		}
+	}
+}
+
+// servePatch patches the object of the path, as parsed by splitObjectPath, and returns the patched object.
+func (s *Server) servePatch(w http.ResponseWriter, r *http.Request, path string, patch []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resourcePath, namespace, name := splitObjectPath(path)
+
+	obj := s.find(resourcePath, namespace, name)
+	if obj == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var patched []byte
+
+	switch types.PatchType(r.Header.Get("Content-Type")) {
+	case types.JSONPatchType:
+		var decodedPatch jsonpatch.Patch
+		if decodedPatch, err = jsonpatch.DecodePatch(patch); err == nil {
+			patched, err = decodedPatch.Apply(original)
+		}
+	// the objects are unstructured, so strategic merge patches are applied as merge patches
+	case types.MergePatchType, types.StrategicMergePatchType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	default:
+		http.Error(w, "Unsupported Media Type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to apply the patch: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	patchedObj := &unstructured.Unstructured{}
+	if err := patchedObj.UnmarshalJSON(patched); err != nil {
+		http.Error(w, fmt.Sprintf("invalid patched object: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	s.apply(resourcePath, patchedObj)
+
+	writeJSON(w, http.StatusOK, patchedObj.Object)
+}
+
+// serveDelete deletes the object of the path, as parsed by splitObjectPath, and returns the deleted object.
+func (s *Server) serveDelete(w http.ResponseWriter, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resourcePath, namespace, name := splitObjectPath(path)
+
+	obj := s.find(resourcePath, namespace, name)
+	if obj == nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	s.remove(resourcePath, obj.GetNamespace(), obj.GetName())
+
+	writeJSON(w, http.StatusOK, obj.Object)
+}
+
+// find returns the object of the resource path with the namespace and the name. The lock must be held.
+func (s *Server) find(resourcePath, namespace, name string) *unstructured.Unstructured {
+	for _, obj := range s.objects[resourcePath] {
+		if obj.GetNamespace() == namespace && obj.GetName() == name {
+			return obj
+		}
+	}
+
+	return nil
+}
+
+// splitObjectPath returns the resource path, the namespace and the name of the path of an object, which is the
+// resource path followed by the name of the object, or by namespaces/NAMESPACE/NAME for a namespaced object
+func splitObjectPath(path string) (string, string, string) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return "", "", path
+	}
+
+	resourcePath, name := path[:i], path[i+1:]
+
+	if i = strings.LastIndex(resourcePath, "/namespaces/"); i >= 0 {
+		return resourcePath[:i], resourcePath[i+len("/namespaces/"):], name
+	}
+
+	return resourcePath, "", name
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	//nolint:errcheck
+	w.Write(data)
+}
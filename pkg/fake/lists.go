@@ -0,0 +1,182 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fake
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// column is a column of the tables of a resource path
+type column struct {
+	definition metav1.TableColumnDefinition
+	cell       func(obj *unstructured.Unstructured) interface{}
+}
+
+// tableColumns are the columns of the tables of the resource paths served as tables, the printer columns of
+// the ManagedCluster CRD for managed clusters. Other resource paths are served as lists, like by Non-K8s API.
+var tableColumns = map[string][]column{
+	client.ManagedClustersPath: {
+		{
+			definition: metav1.TableColumnDefinition{Name: "Name", Type: "string", Format: "name"},
+			cell:       func(obj *unstructured.Unstructured) interface{} { return obj.GetName() },
+		},
+		{
+			definition: metav1.TableColumnDefinition{Name: "Hub Accepted", Type: "boolean"},
+			cell: func(obj *unstructured.Unstructured) interface{} {
+				accepted, _, _ := unstructured.NestedBool(obj.Object, "spec", "hubAcceptsClient")
+				return accepted
+			},
+		},
+		{
+			definition: metav1.TableColumnDefinition{Name: "Managed Cluster URLs", Type: "string"},
+			cell:       managedClusterURLs,
+		},
+		{
+			definition: metav1.TableColumnDefinition{Name: "Joined", Type: "string"},
+			cell:       conditionStatus("ManagedClusterJoined"),
+		},
+		{
+			definition: metav1.TableColumnDefinition{Name: "Available", Type: "string"},
+			cell:       conditionStatus("ManagedClusterConditionAvailable"),
+		},
+		{
+			definition: metav1.TableColumnDefinition{Name: "Age", Type: "date"},
+			cell:       age,
+		},
+	},
+}
+
+// toArray returns the objects as a JSON array, the way Non-K8s API returns whole lists
+func toArray(objs []*unstructured.Unstructured) []interface{} {
+	array := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		array = append(array, obj.Object)
+	}
+
+	return array
+}
+
+// paginate returns at most size objects from the start, with the index of the next object as the continue token
+// if there are more objects. A size of 0 returns all the objects from the start.
+func paginate(objs []*unstructured.Unstructured, start, size int) ([]*unstructured.Unstructured, string) {
+	end := len(objs)
+	if size > 0 && start+size < end {
+		end = start + size
+	}
+
+	continueToken := ""
+	if end < len(objs) {
+		continueToken = strconv.Itoa(end)
+	}
+
+	return objs[start:end], continueToken
+}
+
+// toList returns a list of the objects, continued by the continue token if not empty
+func toList(objs []*unstructured.Unstructured, continueToken string) map[string]interface{} {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+
+	if continueToken != "" {
+		list.SetContinue(continueToken)
+	}
+
+	list.Object["items"] = toArray(objs)
+
+	return list.Object
+}
+
+// toTable returns the objects as a table with the columns. The rows include the whole objects if includeObject
+// is true, or else only their metadata, like the default of the API server.
+func toTable(columns []column, objs []*unstructured.Unstructured, includeObject bool) *metav1.Table {
+	table := &metav1.Table{}
+	table.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("Table"))
+
+	for _, column := range columns {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, column.definition)
+	}
+
+	for _, obj := range objs {
+		cells := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			cells = append(cells, column.cell(obj))
+		}
+
+		rowObject := obj
+		if !includeObject {
+			rowObject = toPartialObjectMetadata(obj)
+		}
+
+		// the objects of rows are marshaled only from their raw JSON, unstructured objects always marshal
+		raw, _ := rowObject.MarshalJSON()
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Raw: raw},
+		})
+	}
+
+	return table
+}
+
+// toPartialObjectMetadata returns the metadata of the object, as included in the rows of tables by default
+func toPartialObjectMetadata(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	metadata := &unstructured.Unstructured{Object: map[string]interface{}{"metadata": obj.Object["metadata"]}}
+	metadata.SetAPIVersion(metav1.SchemeGroupVersion.String())
+	metadata.SetKind("PartialObjectMetadata")
+
+	return metadata
+}
+
+func age(obj *unstructured.Unstructured) interface{} {
+	creationTimestamp := obj.GetCreationTimestamp()
+	if creationTimestamp.IsZero() {
+		return pluginutil.UnknownValue
+	}
+
+	return duration.HumanDuration(time.Since(creationTimestamp.Time))
+}
+
+func managedClusterURLs(obj *unstructured.Unstructured) interface{} {
+	configs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "managedClusterClientConfigs")
+
+	urls := make([]string, 0, len(configs))
+
+	for _, config := range configs {
+		if configMap, ok := config.(map[string]interface{}); ok {
+			if url, ok := configMap["url"].(string); ok {
+				urls = append(urls, url)
+			}
+		}
+	}
+
+	return strings.Join(urls, ",")
+}
+
+// conditionStatus returns a cell with the status of the condition of the type
+func conditionStatus(conditionType string) func(obj *unstructured.Unstructured) interface{} {
+	return func(obj *unstructured.Unstructured) interface{} {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+		for _, condition := range conditions {
+			if conditionMap, ok := condition.(map[string]interface{}); ok && conditionMap["type"] == conditionType {
+				if status, ok := conditionMap["status"].(string); ok {
+					return status
+				}
+			}
+		}
+
+		return ""
+	}
+}
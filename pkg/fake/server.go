@@ -0,0 +1,267 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package fake provides an in-process fake of Non-K8s API of the hub of hubs, built on httptest, for tests and
+// for running the commands without a hub of hubs.
+package fake
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// Token is the bearer token the server accepts
+	Token = "fake-token"
+
+	// apiPath is the path of Non-K8s API in the URL, as used by the client
+	apiPath = "/multicloud/hub-of-hubs-nonk8s-api/"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method      string
+	Path        string
+	Query       url.Values
+	ContentType string
+	Body        []byte
+}
+
+// Error is an error injected into the responses of the server.
+type Error struct {
+	// Method is the method of the failing requests, or empty for all the methods
+	Method string
+	// Path is the resource path of the failing requests, e.g. managedclusters
+	Path string
+	// Watch is true if only the watch requests fail
+	Watch bool
+	// StatusCode is the status of the failing responses
+	StatusCode int
+	// Message is the body of the failing responses
+	Message string
+	// Times is the number of requests that fail, or 0 for all the requests
+	Times int
+}
+
+// Server is a fake Non-K8s API. It serves the lists of the objects of each resource path, as JSON arrays,
+// tables or pages, watch streams of the objects and patches of the objects. Resource paths without objects
+// serve raw bodies, e.g. the kubeconfigs of the managed clusters.
+type Server struct {
+	server *httptest.Server
+	stop   chan struct{}
+
+	mu       sync.Mutex
+	objects  map[string][]*unstructured.Unstructured
+	raw      map[string][]byte
+	watchers map[string][]*watcher
+	errors   []*Error
+	requests []Request
+	pageSize int
+}
+
+// watcher receives the events of a watch stream
+type watcher struct {
+	events chan *client.WatchEvent
+	done   chan struct{}
+}
+
+// NewServer starts a server without objects. Close must be called to stop it.
+func NewServer() *Server {
+	s := &Server{
+		stop:     make(chan struct{}),
+		objects:  map[string][]*unstructured.Unstructured{},
+		raw:      map[string][]byte{},
+		watchers: map[string][]*watcher{},
+	}
+	s.server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Close ends the watch streams and stops the server.
+func (s *Server) Close() {
+	close(s.stop)
+	s.server.Close()
+}
+
+// URL returns the URL of the server, to be used as the URL of Non-K8s API.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// CertificatePEM returns the certificate of the server in PEM format, to be used as the CA file of a client.
+func (s *Server) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+}
+
+// Config returns the config of a client of the server.
+func (s *Server) Config() *client.Config {
+	return &client.Config{
+		URL:         s.server.URL,
+		BearerToken: Token,
+		Transport:   s.server.Client().Transport,
+	}
+}
+
+// SetObjects replaces the objects of the resource path, without watch events.
+func (s *Server) SetObjects(resourcePath string, objs ...*unstructured.Unstructured) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[resourcePath] = deepCopy(objs)
+}
+
+// Objects returns copies of the objects of the resource path.
+func (s *Server) Objects(resourcePath string) []*unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return deepCopy(s.objects[resourcePath])
+}
+
+// SetRaw sets the JSON body served for the resource path, if it has no objects.
+func (s *Server) SetRaw(resourcePath string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.raw[resourcePath] = body
+}
+
+// Apply adds the object to the resource path, or replaces the object with the same namespace and name, and
+// sends the added or modified event to the watch streams of the resource path.
+func (s *Server) Apply(resourcePath string, obj *unstructured.Unstructured) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apply(resourcePath, obj.DeepCopy())
+}
+
+// Remove removes the object with the namespace and the name from the resource path, and sends the deleted
+// event to the watch streams of the resource path. It returns false if there is no such object.
+func (s *Server) Remove(resourcePath, namespace, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(resourcePath, namespace, name) != nil
+}
+
+// SetPageSize makes the server return lists in pages of the size, even if the requests specify no limit.
+// A size of 0 returns whole lists unless the requests specify a limit.
+func (s *Server) SetPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = pageSize
+}
+
+// InjectError makes the requests matching the error fail with its status and message.
+func (s *Server) InjectError(e Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, &e)
+}
+
+// ClearErrors removes the injected errors.
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = nil
+}
+
+// Requests returns the requests received by the server, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// apply adds or replaces the object and sends the event, the lock must be held
+func (s *Server) apply(resourcePath string, obj *unstructured.Unstructured) {
+	eventType := watch.Added
+	objs := s.objects[resourcePath]
+
+	if i := index(objs, obj.GetNamespace(), obj.GetName()); i >= 0 {
+		objs[i] = obj
+		eventType = watch.Modified
+	} else {
+		s.objects[resourcePath] = append(objs, obj)
+	}
+
+	s.send(resourcePath, eventType, obj)
+}
+
+// remove removes the object and sends the event, the lock must be held. It returns the removed object.
+func (s *Server) remove(resourcePath, namespace, name string) *unstructured.Unstructured {
+	objs := s.objects[resourcePath]
+
+	i := index(objs, namespace, name)
+	if i < 0 {
+		return nil
+	}
+
+	obj := objs[i]
+	s.objects[resourcePath] = append(objs[:i:i], objs[i+1:]...)
+	s.send(resourcePath, watch.Deleted, obj)
+
+	return obj
+}
+
+// send sends the event to the watchers of the resource path, the lock must be held
+func (s *Server) send(resourcePath string, eventType watch.EventType, obj *unstructured.Unstructured) {
+	for _, w := range s.watchers[resourcePath] {
+		select {
+		case w.events <- &client.WatchEvent{Type: eventType, Object: obj.DeepCopy()}:
+		case <-w.done:
+		case <-s.stop:
+		}
+	}
+}
+
+// injectedError returns the injected error matching the request if any, the lock must be held
+func (s *Server) injectedError(method, resourcePath string, watch bool) *Error {
+	for i, e := range s.errors {
+		if (e.Method != "" && e.Method != method) || e.Path != resourcePath || (e.Watch && !watch) {
+			continue
+		}
+
+		if e.Times > 0 {
+			e.Times--
+			if e.Times == 0 {
+				s.errors = append(s.errors[:i:i], s.errors[i+1:]...)
+			}
+		}
+
+		return e
+	}
+
+	return nil
+}
+
+func index(objs []*unstructured.Unstructured, namespace, name string) int {
+	for i, obj := range objs {
+		if obj.GetNamespace() == namespace && obj.GetName() == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func deepCopy(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	copies := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		copies = append(copies, obj.DeepCopy())
+	}
+
+	return copies
+}
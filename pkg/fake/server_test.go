@@ -0,0 +1,390 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/client"
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/fake"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newServer(t *testing.T) (*fake.Server, *client.Client) {
+	t.Helper()
+
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	if err := server.LoadDefaultFixtures(); err != nil {
+		t.Fatalf("unable to load fixtures: %v", err)
+	}
+
+	c, err := client.New(server.Config())
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	return server, c
+}
+
+func names(objs []*unstructured.Unstructured) string {
+	result := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.GetName())
+	}
+
+	return strings.Join(result, ",")
+}
+
+func TestList(t *testing.T) {
+	_, c := newServer(t)
+
+	managedClusters, err := c.ListManagedClusters(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(managedClusters) != 4 || managedClusters[0].Name != "cluster1" {
+		t.Errorf("unexpected managed clusters: %v", managedClusters)
+	}
+
+	policies, err := c.List(context.TODO(), "policies")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := names(policies); got != "policy-pod" {
+		t.Errorf("unexpected policies: %s", got)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	server, c := newServer(t)
+	server.SetPageSize(3)
+
+	objs, err := c.List(context.TODO(), client.ManagedClustersPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := names(objs); got != "cluster1,cluster2,cluster3,cluster4" {
+		t.Errorf("unexpected managed clusters: %s", got)
+	}
+
+	var continued []string
+
+	for _, request := range server.Requests() {
+		continued = append(continued, request.Query.Get("continue"))
+	}
+
+	if got := strings.Join(continued, ","); got != ",3" {
+		t.Errorf("unexpected continue tokens: %q", got)
+	}
+}
+
+func TestTable(t *testing.T) {
+	_, c := newServer(t)
+
+	req, err := c.NewRequest(context.TODO(), "GET", client.ManagedClustersPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req.Header.Add("Accept", "application/json;as=Table;v=v1;g=meta.k8s.io")
+
+	body, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	table := &unstructured.Unstructured{}
+	if err := table.UnmarshalJSON(body); err != nil {
+		t.Fatalf("unable to decode table: %v", err)
+	}
+
+	if table.GetKind() != "Table" {
+		t.Fatalf("unexpected kind: %s", table.GetKind())
+	}
+
+	rows, _, _ := unstructured.NestedSlice(table.Object, "rows")
+	if len(rows) != 4 {
+		t.Fatalf("unexpected number of rows: %d", len(rows))
+	}
+
+	cells, _, _ := unstructured.NestedSlice(rows[1].(map[string]interface{}), "cells")
+	if len(cells) != 6 || cells[0] != "cluster2" || cells[4] != "Unknown" {
+		t.Errorf("unexpected cells: %v", cells)
+	}
+}
+
+func TestRaw(t *testing.T) {
+	_, c := newServer(t)
+
+	config, err := c.GetManagedClusterKubeconfig(context.TODO(), "cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.CurrentContext != "ctx" || config.Clusters["c"].Server != "https://api.cluster1.example.com:6443" {
+		t.Errorf("unexpected kubeconfig: %v", config)
+	}
+
+	if _, err := c.GetManagedClusterKubeconfig(context.TODO(), "cluster4"); !client.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	server, c := newServer(t)
+
+	patched, err := c.Patch(context.TODO(), client.ManagedClustersPath, "", "cluster1", types.MergePatchType,
+		[]byte(`{"metadata":{"labels":{"environment":"prod"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if patched.GetLabels()["environment"] != "prod" {
+		t.Errorf("unexpected patched labels: %v", patched.GetLabels())
+	}
+
+	_, err = c.Patch(context.TODO(), client.ManagedClustersPath, "", "cluster1", types.JSONPatchType,
+		[]byte(`[{"op":"remove","path":"/metadata/labels/cloud"}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	managedCluster, err := c.Get(context.TODO(), client.ManagedClustersPath, "", "cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels := managedCluster.GetLabels()
+	if labels["environment"] != "prod" || labels["cloud"] != "" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+
+	if got := server.Objects(client.ManagedClustersPath)[0].GetLabels(); got["environment"] != "prod" {
+		t.Errorf("unexpected labels of the object of the server: %v", got)
+	}
+
+	_, err = c.Patch(context.TODO(), client.ManagedClustersPath, "", "nosuchcluster", types.MergePatchType, []byte(`{}`))
+	if !client.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	server, c := newServer(t)
+
+	if err := c.Delete(context.TODO(), "placements", "default", "placement-all"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := names(server.Objects("placements")); got != "placement-prod" {
+		t.Errorf("unexpected placements: %s", got)
+	}
+
+	if err := c.Delete(context.TODO(), "placements", "default", "placement-prod"); !client.IsNotFound(err) {
+		t.Errorf("expected not found in another namespace, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	server, c := newServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var events []string
+
+	done, err := c.Watch(ctx, client.ManagedClustersPath, func(event *client.WatchEvent) (bool, error) {
+		events = append(events, string(event.Type)+" "+event.Object.GetName())
+
+		switch len(events) {
+		case 4:
+			// all the managed clusters were added, so change them
+			go func() {
+				modified := server.Objects(client.ManagedClustersPath)[1]
+				modified.SetLabels(map[string]string{"environment": "dev"})
+				server.Apply(client.ManagedClustersPath, modified)
+				server.Remove(client.ManagedClustersPath, "", "cluster3")
+			}()
+		case 6:
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil || !done {
+		t.Fatalf("unexpected result: %v, %v", done, err)
+	}
+
+	expected := "ADDED cluster1,ADDED cluster2,ADDED cluster3,ADDED cluster4,MODIFIED cluster2,DELETED cluster3"
+	if got := strings.Join(events, ","); got != expected {
+		t.Errorf("unexpected events: %s", got)
+	}
+}
+
+func TestWatchNotSupported(t *testing.T) {
+	_, c := newServer(t)
+
+	_, err := c.Watch(context.TODO(), "nosuchresources", func(*client.WatchEvent) (bool, error) {
+		return true, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), client.ErrWatchNotSupported.Error()) {
+		t.Errorf("expected watch not supported, got %v", err)
+	}
+}
+
+func TestWatchEmptyStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(&client.Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	// an accepted watch whose stream ended before any event is supported, and can be watched again
+	done, err := c.Watch(context.TODO(), client.ManagedClustersPath, func(*client.WatchEvent) (bool, error) {
+		return true, nil
+	})
+	if done || err != nil {
+		t.Errorf("expected the watch ended without error, got %v, %v", done, err)
+	}
+}
+
+func TestWatchFailure(t *testing.T) {
+	server, c := newServer(t)
+
+	server.InjectError(fake.Error{Path: client.ManagedClustersPath, StatusCode: http.StatusForbidden, Message: "forbidden"})
+
+	_, err := c.Watch(context.TODO(), client.ManagedClustersPath, func(*client.WatchEvent) (bool, error) {
+		return true, nil
+	})
+
+	var statusError *client.StatusError
+	if errors.Is(err, client.ErrWatchNotSupported) || !errors.As(err, &statusError) ||
+		statusError.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status forbidden, got %v", err)
+	}
+}
+
+func TestInjectError(t *testing.T) {
+	server, c := newServer(t)
+
+	server.InjectError(fake.Error{
+		Method:     "GET",
+		Path:       client.ManagedClustersPath,
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "leaf hubs unavailable",
+		Times:      1,
+	})
+
+	_, err := c.List(context.TODO(), client.ManagedClustersPath)
+
+	var statusError *client.StatusError
+	if !errors.As(err, &statusError) || statusError.StatusCode != http.StatusServiceUnavailable ||
+		statusError.Message != "leaf hubs unavailable" {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+
+	if _, err := c.List(context.TODO(), client.ManagedClustersPath); err != nil {
+		t.Errorf("expected the error to be injected once, got %v", err)
+	}
+
+	server.InjectError(fake.Error{Path: "policies", StatusCode: http.StatusForbidden})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.List(context.TODO(), "policies"); err == nil {
+			t.Errorf("expected error on request %d", i)
+		}
+	}
+
+	server.ClearErrors()
+
+	if _, err := c.List(context.TODO(), "policies"); err != nil {
+		t.Errorf("unexpected error after clearing errors: %v", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	server, _ := newServer(t)
+
+	config := server.Config()
+	config.BearerToken = "wrong-token"
+
+	c, err := client.New(config)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	_, err = c.List(context.TODO(), client.ManagedClustersPath)
+
+	var statusError *client.StatusError
+	if !errors.As(err, &statusError) || statusError.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+}
+
+func TestRemoveMissing(t *testing.T) {
+	server, _ := newServer(t)
+
+	if server.Remove(client.ManagedClustersPath, "", "nosuchcluster") {
+		t.Errorf("unexpected removal of a missing object")
+	}
+
+	if got := len(server.Objects(client.ManagedClustersPath)); got != 4 {
+		t.Errorf("unexpected number of managed clusters: %d", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	server, _ := newServer(t)
+
+	config := server.Config()
+	config.Retries = 2
+	config.RetryBackoff = time.Millisecond
+
+	c, err := client.New(config)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	server.InjectError(fake.Error{Path: client.ManagedClustersPath, StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+	if _, err := c.List(context.TODO(), client.ManagedClustersPath); err != nil {
+		t.Fatalf("expected the request to be retried, got %v", err)
+	}
+
+	server.InjectError(fake.Error{Path: client.ManagedClustersPath, StatusCode: http.StatusServiceUnavailable, Times: 3})
+
+	if _, err := c.List(context.TODO(), client.ManagedClustersPath); err == nil {
+		t.Errorf("expected an error after the retries")
+	}
+
+	server.ClearErrors()
+	server.InjectError(fake.Error{
+		Method:     http.MethodPatch,
+		Path:       client.ManagedClustersPath + "/cluster1",
+		StatusCode: http.StatusServiceUnavailable,
+		Times:      1,
+	})
+
+	// patches may not be idempotent, so they are not retried
+	_, err = c.Patch(context.TODO(), client.ManagedClustersPath, "", "cluster1", types.MergePatchType, []byte(`{}`))
+	if err == nil {
+		t.Errorf("expected the patch not to be retried")
+	}
+}
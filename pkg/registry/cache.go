@@ -0,0 +1,87 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultCacheTTL is the time the discovered resources are cached for, as by kubectl
+const DefaultCacheTTL = 10 * time.Minute
+
+const (
+	dirPermissions  = 0o750
+	filePermissions = 0o600
+)
+
+// unsafeFileNameCharacters are replaced in the names of the cache files
+var unsafeFileNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// DiscoveryCache caches the resources discovered from Non-K8s API in a file per kubeconfig context and URL of
+// Non-K8s API, since the URL of a context may be changed by the settings of the plugin
+type DiscoveryCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDiscoveryCache returns a cache in the directory, whose entries expire after the ttl.
+func NewDiscoveryCache(dir string, ttl time.Duration) *DiscoveryCache {
+	return &DiscoveryCache{dir: dir, ttl: ttl}
+}
+
+// Get returns the resources cached for the context and the URL. Expired resources are returned only if
+// allowExpired is true.
+func (c *DiscoveryCache) Get(contextName, url string, allowExpired bool) ([]metav1.APIResourceList, bool) {
+	fileName := c.fileName(contextName, url)
+
+	info, err := os.Stat(fileName)
+	if err != nil || (!allowExpired && time.Since(info.ModTime()) > c.ttl) {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, false
+	}
+
+	var apiResourceLists []metav1.APIResourceList
+	if err := json.Unmarshal(data, &apiResourceLists); err != nil {
+		return nil, false
+	}
+
+	return apiResourceLists, true
+}
+
+// Set caches the resources discovered for the context and the URL.
+func (c *DiscoveryCache) Set(contextName, url string, apiResourceLists []metav1.APIResourceList) error {
+	data, err := json.Marshal(apiResourceLists)
+	if err != nil {
+		return fmt.Errorf("unable to encode the API resources: %w", err)
+	}
+
+	fileName := c.fileName(contextName, url)
+
+	if err := os.MkdirAll(filepath.Dir(fileName), dirPermissions); err != nil {
+		return fmt.Errorf("unable to create the cache directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(fileName, data, filePermissions); err != nil {
+		return fmt.Errorf("unable to write the cache file: %w", err)
+	}
+
+	return nil
+}
+
+func (c *DiscoveryCache) fileName(contextName, url string) string {
+	return filepath.Join(c.dir, unsafeFileNameCharacters.ReplaceAllString(contextName, "_"),
+		unsafeFileNameCharacters.ReplaceAllString(url, "_")+".json")
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package registry
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// FromAPIResources returns a registry of the resources discovered from Non-K8s API. The discovered resources
+// that are registered in the built-in registry keep its columns, the others are printed by the default printer.
+// The built-in default resource stays the default resource if discovered.
+func FromAPIResources(apiResourceLists []metav1.APIResourceList, builtin *Registry) (*Registry, error) {
+	discovered := New()
+
+	if defaultResource := builtin.Default(); defaultResource != nil {
+		for _, apiResourceList := range apiResourceLists {
+			for i := range apiResourceList.APIResources {
+				if apiResourceList.APIResources[i].Name == defaultResource.Path {
+					if err := discovered.registerAPIResource(apiResourceList.GroupVersion,
+						&apiResourceList.APIResources[i], builtin); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	for _, apiResourceList := range apiResourceLists {
+		for i := range apiResourceList.APIResources {
+			if _, found := discovered.byPath(apiResourceList.APIResources[i].Name); found {
+				continue
+			}
+			if err := discovered.registerAPIResource(apiResourceList.GroupVersion, &apiResourceList.APIResources[i],
+				builtin); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return discovered, nil
+}
+
+func (r *Registry) registerAPIResource(groupVersion string, apiResource *metav1.APIResource, builtin *Registry) error {
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil {
+		return fmt.Errorf("invalid group version of %s: %w", apiResource.Name, err)
+	}
+
+	if apiResource.Group != "" || apiResource.Version != "" {
+		gv = schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}
+	}
+
+	scope := meta.RESTScopeRoot
+	if apiResource.Namespaced {
+		scope = meta.RESTScopeNamespace
+	}
+
+	resource := &Resource{
+		Mapping: &meta.RESTMapping{
+			Resource:         gv.WithResource(apiResource.Name),
+			GroupVersionKind: gv.WithKind(apiResource.Kind),
+			Scope:            scope,
+		},
+		Path:         apiResource.Name,
+		SingularName: apiResource.SingularName,
+		ShortNames:   apiResource.ShortNames,
+		Verbs:        apiResource.Verbs,
+	}
+
+	if registered, found := builtin.byPath(apiResource.Name); found {
+		resource.ColumnDefinitions = registered.ColumnDefinitions
+		resource.Cells = registered.Cells
+		resource.RelatedPath = registered.RelatedPath
+		if resource.SingularName == "" {
+			resource.SingularName = registered.SingularName
+		}
+	}
+
+	return r.Register(resource)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// The verbs a resource may allow, as in Kubernetes API discovery
+const (
+	VerbGet   = "get"
+	VerbList  = "list"
+	VerbWatch = "watch"
+	VerbPatch = "patch"
+)
+
+var (
+	errAlreadyRegistered = errors.New("resource is already registered")
+	errVerbNotAllowed    = errors.New("the server does not allow this method on the requested resource")
+	errUnknownType       = errors.New("the server doesn't have a resource type")
+)
+
+// Resource describes a resource served by Non-K8s API
+type Resource struct {
+	Mapping *meta.RESTMapping
+	// Path is the path of the resource in Non-K8s API, also used as the resource name in the command line
+	Path string
+	// SingularName and ShortNames are the other names of the resource in the command line
+	SingularName string
+	ShortNames   []string
+	// Verbs are the verbs Non-K8s API allows on the resource
+	Verbs []string
+	// ColumnDefinitions and Cells are used to print the resource as a table if Non-K8s API
+	// does not return a table. If Cells is nil, the resource is printed by the default printer.
+	ColumnDefinitions []metav1.TableColumnDefinition
+	Cells             func(obj *unstructured.Unstructured, related []*unstructured.Unstructured) []interface{}
+	// RelatedPath is the path of the resource whose objects are passed to Cells as related objects, e.g. the
+	// placement decisions of placements, empty if Cells needs no related objects
+	RelatedPath string
+}
+
+// Matches returns true if the name is the path, the singular name, a short name or the kind of the resource
+func (r *Resource) Matches(name string) bool {
+	name = strings.ToLower(name)
+
+	if name == r.Path || name == r.SingularName {
+		return true
+	}
+
+	for _, shortName := range r.ShortNames {
+		if name == shortName {
+			return true
+		}
+	}
+
+	if r.Mapping == nil {
+		return false
+	}
+
+	return name == r.Mapping.Resource.Resource ||
+		name == strings.ToLower(r.Mapping.GroupVersionKind.Kind) ||
+		name == strings.ToLower(r.Mapping.GroupVersionKind.GroupKind().String()) ||
+		name == r.Mapping.Resource.GroupResource().String()
+}
+
+// Namespaced returns true if the resource is namespaced
+func (r *Resource) Namespaced() bool {
+	return r.Mapping != nil && r.Mapping.Scope != nil && r.Mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
+
+// Allows returns true if Non-K8s API allows the verb on the resource
+func (r *Resource) Allows(verb string) bool {
+	return sets.NewString(r.Verbs...).Has(verb)
+}
+
+// Name returns the name of the resource qualified by its group, as printed by kubectl, e.g. policy.example.io
+func (r *Resource) Name() string {
+	if r.Mapping == nil {
+		return r.Path
+	}
+
+	return fmt.Sprintf("%s.%s", strings.ToLower(r.Mapping.GroupVersionKind.Kind), r.Mapping.GroupVersionKind.Group)
+}
+
+// Registry holds the resources served by Non-K8s API. The first registered resource is the default resource,
+// used by the commands if no resource type is specified.
+type Registry struct {
+	resources []*Resource
+
+	discover     func() (*Registry, error)
+	discoverOnce sync.Once
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// Register adds the resource to the registry, failing if a resource with the same path is already registered.
+func (r *Registry) Register(resource *Resource) error {
+	if _, found := r.byPath(resource.Path); found {
+		return fmt.Errorf("%w: %s", errAlreadyRegistered, resource.Path)
+	}
+
+	r.resources = append(r.resources, resource)
+
+	return nil
+}
+
+// SetDiscovery makes the registry replace its resources, when they are first used, with the resources of the
+// registry returned by discover, e.g. the resources discovered from Non-K8s API. The registered resources are kept
+// if discover fails or returns no resources.
+func (r *Registry) SetDiscovery(discover func() (*Registry, error)) {
+	r.discover = discover
+}
+
+// Resources returns the registered resources in the order of their registration.
+func (r *Registry) Resources() []*Resource {
+	r.runDiscovery()
+
+	return r.resources
+}
+
+// Default returns the default resource, nil if no resource is registered.
+func (r *Registry) Default() *Resource {
+	r.runDiscovery()
+
+	if len(r.resources) == 0 {
+		return nil
+	}
+
+	return r.resources[0]
+}
+
+// Lookup returns the resource matching the name.
+func (r *Registry) Lookup(name string) (*Resource, bool) {
+	r.runDiscovery()
+
+	for _, resource := range r.resources {
+		if resource.Matches(name) {
+			return resource, true
+		}
+	}
+
+	return nil, false
+}
+
+// runDiscovery replaces the resources with the discovered resources once, if discovery is set
+func (r *Registry) runDiscovery() {
+	r.discoverOnce.Do(func() {
+		if r.discover == nil {
+			return
+		}
+
+		discovered, err := r.discover()
+		if err != nil || len(discovered.resources) == 0 {
+			return
+		}
+
+		r.resources = discovered.resources
+	})
+}
+
+// byPath returns the resource with the path
+func (r *Registry) byPath(path string) (*Resource, bool) {
+	for _, resource := range r.resources {
+		if resource.Path == path {
+			return resource, true
+		}
+	}
+
+	return nil, false
+}
+
+// ResourceFromArgs returns the resource specified by the first argument, or the default resource if there are no
+// arguments, with the rest of the arguments. Names are never taken as names of the default resource, so that a
+// mistyped resource type is not mistaken for a name. It fails if the first argument is not a resource type, or if
+// Non-K8s API does not allow the verb on the resource.
+func (r *Registry) ResourceFromArgs(args []string, verb string) (*Resource, []string, error) {
+	resource, rest := r.Default(), args
+	if len(args) > 0 {
+		matched, found := r.Lookup(args[0])
+		if !found {
+			return nil, nil, fmt.Errorf("%w %q", errUnknownType, args[0])
+		}
+
+		resource, rest = matched, args[1:]
+	}
+
+	if resource == nil {
+		return nil, nil, fmt.Errorf("%w: no resources are registered", errVerbNotAllowed)
+	}
+
+	if !resource.Allows(verb) {
+		return nil, nil, fmt.Errorf("%w: %s %s", errVerbNotAllowed, verb, resource.Path)
+	}
+
+	return resource, rest, nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package registry_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stolostron/hub-of-hubs-cli-plugins/pkg/registry"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newRegistry(t *testing.T) *registry.Registry {
+	t.Helper()
+
+	resourceRegistry := registry.New()
+
+	for _, resource := range []*registry.Resource{
+		{
+			Path:         "managedclusters",
+			SingularName: "managedcluster",
+			ShortNames:   []string{"mcl", "mcls"},
+			Verbs:        []string{registry.VerbGet, registry.VerbList, registry.VerbPatch},
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{
+					Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters",
+				},
+				GroupVersionKind: schema.GroupVersionKind{
+					Group: "cluster.open-cluster-management.io", Version: "v1", Kind: "ManagedCluster",
+				},
+			},
+		},
+		{
+			Path:         "policies",
+			SingularName: "policy",
+			ShortNames:   []string{"plc"},
+			Verbs:        []string{registry.VerbGet, registry.VerbList},
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{
+					Group: "policy.open-cluster-management.io", Version: "v1", Resource: "policies",
+				},
+				GroupVersionKind: schema.GroupVersionKind{
+					Group: "policy.open-cluster-management.io", Version: "v1", Kind: "Policy",
+				},
+			},
+		},
+	} {
+		if err := resourceRegistry.Register(resource); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return resourceRegistry
+}
+
+func TestResourceFromArgs(t *testing.T) {
+	resourceRegistry := newRegistry(t)
+
+	tests := []struct {
+		name          string
+		args          []string
+		verb          string
+		expectedPath  string
+		expectedNames []string
+		expectedErr   string
+	}{
+		{name: "default", verb: registry.VerbList, expectedPath: "managedclusters"},
+		{name: "path", args: []string{"policies"}, verb: registry.VerbList, expectedPath: "policies"},
+		{name: "singular name", args: []string{"policy", "p1"}, verb: registry.VerbGet, expectedPath: "policies",
+			expectedNames: []string{"p1"}},
+		{name: "short name", args: []string{"plc"}, verb: registry.VerbList, expectedPath: "policies"},
+		{name: "second short name", args: []string{"mcls", "c1", "c2"}, verb: registry.VerbPatch,
+			expectedPath: "managedclusters", expectedNames: []string{"c1", "c2"}},
+		{name: "kind", args: []string{"ManagedCluster"}, verb: registry.VerbList, expectedPath: "managedclusters"},
+		{name: "group resource", args: []string{"policies.policy.open-cluster-management.io"},
+			verb: registry.VerbList, expectedPath: "policies"},
+		{name: "group kind", args: []string{"Policy.policy.open-cluster-management.io"},
+			verb: registry.VerbList, expectedPath: "policies"},
+		{name: "upper case", args: []string{"PLC"}, verb: registry.VerbList, expectedPath: "policies"},
+		{name: "name without type", args: []string{"cluster1"}, verb: registry.VerbGet,
+			expectedErr: `the server doesn't have a resource type "cluster1"`},
+		{name: "verb not allowed", args: []string{"policies", "p1"}, verb: registry.VerbPatch,
+			expectedErr: "the server does not allow this method on the requested resource: patch policies"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			resource, names, err := resourceRegistry.ResourceFromArgs(test.args, test.verb)
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("unexpected error: %v, expected %q", err, test.expectedErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resource.Path != test.expectedPath {
+				t.Errorf("unexpected resource: %s, expected %s", resource.Path, test.expectedPath)
+			}
+
+			if len(names) > 0 || len(test.expectedNames) > 0 {
+				if !reflect.DeepEqual(names, test.expectedNames) {
+					t.Errorf("unexpected names: %v, expected %v", names, test.expectedNames)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	cache := registry.NewDiscoveryCache(t.TempDir(), time.Minute)
+
+	apiResourceLists := []metav1.APIResourceList{
+		{
+			GroupVersion: "cluster.open-cluster-management.io/v1",
+			APIResources: []metav1.APIResource{{Name: "managedclusters"}},
+		},
+	}
+
+	if err := cache.Set("hub", "https://hub.example.com", apiResourceLists); err != nil {
+		t.Fatal(err)
+	}
+
+	if cached, found := cache.Get("hub", "https://hub.example.com", false); !found ||
+		!reflect.DeepEqual(cached, apiResourceLists) {
+		t.Errorf("unexpected cached resources: %v, found: %t", cached, found)
+	}
+
+	// the URL of the context may be changed by the settings of the plugin
+	if _, found := cache.Get("hub", "https://other.example.com", false); found {
+		t.Error("unexpected cached resources for another URL")
+	}
+}
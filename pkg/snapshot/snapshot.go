@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	pluginutil "github.com/stolostron/hub-of-hubs-cli-plugins/pkg/util"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	metadataFileName = "metadata.yaml"
+	resourceFileExt  = ".json"
+	dirPermissions   = 0o755
+	filePermissions  = 0o644
+)
+
+// ErrResourceNotInSnapshot is returned if the snapshot has no file for the resource path, e.g. if the snapshot was
+// exported before the resource was served
+var ErrResourceNotInSnapshot = errors.New("resource not found in snapshot")
+
+// Metadata describes when, from where and by which version of the plugin a snapshot was exported
+type Metadata struct {
+	Time          time.Time `json:"time"`
+	HubURL        string    `json:"hubURL"`
+	PluginVersion string    `json:"pluginVersion"`
+	Resources     []string  `json:"resources"`
+}
+
+// WriteResource writes the body returned by Non-K8s API for the resource path into the snapshot directory
+func WriteResource(dir, resourcePath string, body []byte) error {
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return fmt.Errorf("unable to create snapshot directory %s: %w", dir, err)
+	}
+
+	if err := ioutil.WriteFile(resourceFile(dir, resourcePath), body, filePermissions); err != nil {
+		return fmt.Errorf("unable to write %s to snapshot: %w", resourcePath, err)
+	}
+
+	return nil
+}
+
+// WriteMetadata writes the metadata of the snapshot into the snapshot directory
+func WriteMetadata(dir string, metadata *Metadata) error {
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("unable to marshal snapshot metadata: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, metadataFileName), data, filePermissions); err != nil {
+		return fmt.Errorf("unable to write snapshot metadata: %w", err)
+	}
+
+	return nil
+}
+
+// ReadMetadata reads the metadata of the snapshot in the directory
+func ReadMetadata(dir string) (*Metadata, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, metadataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshot metadata: %w", err)
+	}
+
+	metadata := &Metadata{}
+	if err := yaml.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("unable to parse snapshot metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// ReadResource reads the body returned by Non-K8s API for the resource path from the snapshot directory
+func ReadResource(dir, resourcePath string) ([]byte, error) {
+	body, err := ioutil.ReadFile(resourceFile(dir, resourcePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s in %s", ErrResourceNotInSnapshot, resourcePath, dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s from snapshot: %w", resourcePath, err)
+	}
+
+	return body, nil
+}
+
+// GetResourceObjects returns the objects of the resource path in the snapshot directory
+func GetResourceObjects(dir, resourcePath string) ([]runtime.Object, error) {
+	body, err := ReadResource(dir, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := pluginutil.GetObjects(body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get objects from snapshot: %w", err)
+	}
+
+	return objs, nil
+}
+
+func resourceFile(dir, resourcePath string) string {
+	return filepath.Join(dir, resourcePath+resourceFileExt)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	// openShiftVersionClaim is the cluster claim of OpenShift managed clusters holding the OpenShift version
+	openShiftVersionClaim = "version.openshift.io"
+	openShiftVersionLabel = "openshiftVersion"
+)
+
+var errNotUnstructured = errors.New("object is not unstructured")
+
+// ToManagedClusters converts the objects returned by Non-K8s API into managed clusters
+func ToManagedClusters(objs []runtime.Object) ([]*clusterv1.ManagedCluster, error) {
+	managedClusters := make([]*clusterv1.ManagedCluster, 0, len(objs))
+
+	for _, obj := range objs {
+		unstructuredObj, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", errNotUnstructured, obj)
+		}
+
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object,
+			managedCluster); err != nil {
+			return nil, fmt.Errorf("failed to convert %s to managed cluster: %w", unstructuredObj.GetName(), err)
+		}
+
+		managedClusters = append(managedClusters, managedCluster)
+	}
+
+	return managedClusters, nil
+}
+
+// GetConditionStatus returns the status of the condition of the managed cluster, Unknown if not found
+func GetConditionStatus(managedCluster *clusterv1.ManagedCluster, conditionType string) metav1.ConditionStatus {
+	condition := meta.FindStatusCondition(managedCluster.Status.Conditions, conditionType)
+	if condition == nil {
+		return metav1.ConditionUnknown
+	}
+
+	return condition.Status
+}
+
+// GetOpenShiftVersion returns the OpenShift version of the managed cluster from its cluster claim or its label,
+// empty if the managed cluster is not an OpenShift cluster
+func GetOpenShiftVersion(managedCluster *clusterv1.ManagedCluster) string {
+	for _, claim := range managedCluster.Status.ClusterClaims {
+		if claim.Name == openShiftVersionClaim {
+			return claim.Value
+		}
+	}
+
+	return managedCluster.GetLabels()[openShiftVersionLabel]
+}
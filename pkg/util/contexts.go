@@ -0,0 +1,126 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ContextAnnotation is the annotation of the objects listed from several kubeconfig contexts, holding the context
+// the object was listed from
+const ContextAnnotation = "mcl.open-cluster-management.io/context"
+
+var (
+	errContextNotFound  = errors.New("context not found")
+	errClusterNotFound  = errors.New("cluster not found")
+	errAuthInfoNotFound = errors.New("user not found")
+	errUnknownURLFormat = errors.New("Unknown format for server URL")
+	errNoToken          = errors.New("No Token found")
+	errNoContexts       = errors.New("no contexts found in the kubeconfig")
+)
+
+// ContextNames returns the names of the contexts of the kubeconfig to query: all its contexts, sorted, if all is
+// true, or else the requested contexts. It fails if a requested context is not in the kubeconfig.
+func ContextNames(config clientcmdapi.Config, requested []string, all bool) ([]string, error) {
+	if !all {
+		var notFound []string
+
+		for _, contextName := range requested {
+			if _, found := config.Contexts[contextName]; !found {
+				notFound = append(notFound, contextName)
+			}
+		}
+
+		if len(notFound) > 0 {
+			return nil, fmt.Errorf("%w: %s", errContextNotFound, strings.Join(notFound, ", "))
+		}
+
+		return requested, nil
+	}
+
+	if len(config.Contexts) == 0 {
+		return nil, errNoContexts
+	}
+
+	contextNames := make([]string, 0, len(config.Contexts))
+	for contextName := range config.Contexts {
+		contextNames = append(contextNames, contextName)
+	}
+
+	sort.Strings(contextNames)
+
+	return contextNames, nil
+}
+
+// NonK8sAPIURL returns the URL of Non-K8s API of the hub of hubs of the context, derived from the URL of its API
+// server
+func NonK8sAPIURL(config clientcmdapi.Config, contextName string) (string, error) {
+	serverURLString, err := apiServerURL(config, contextName)
+	if err != nil {
+		return "", fmt.Errorf("Server URL not found: %w", err)
+	}
+
+	serverURL, err := url.Parse(serverURLString)
+	if err != nil {
+		return "", fmt.Errorf("Unable to parse server URL %s: %w", serverURL, err)
+	}
+
+	hostWithoutPort := strings.Split(serverURL.Host, ":")[0]
+
+	baseDomain := strings.TrimPrefix(hostWithoutPort, "api.")
+	if baseDomain == "" {
+		return "", fmt.Errorf("%w: for %s", errUnknownURLFormat, hostWithoutPort)
+	}
+
+	return fmt.Sprintf("%s://multicloud-console.apps.%s", serverURL.Scheme, baseDomain), nil
+}
+
+// Token returns the token of the user of the context (if token-authentication is used)
+func Token(config clientcmdapi.Config, contextName string) (string, error) {
+	kubeContext, found := config.Contexts[contextName]
+	if !found {
+		return "", fmt.Errorf("%w: for %s", errContextNotFound, contextName)
+	}
+
+	authInfo, found := config.AuthInfos[kubeContext.AuthInfo]
+	if !found {
+		return "", fmt.Errorf("%w: for %s", errAuthInfoNotFound, kubeContext.AuthInfo)
+	}
+
+	if authInfo.Token == "" {
+		return "", fmt.Errorf("%w: for %s", errNoToken, kubeContext.AuthInfo)
+	}
+
+	return authInfo.Token, nil
+}
+
+// Cluster returns the cluster of the context, i.e. the API server of the hub of hubs and its certificate authority
+func Cluster(config clientcmdapi.Config, contextName string) (*clientcmdapi.Cluster, error) {
+	kubeContext, found := config.Contexts[contextName]
+	if !found {
+		return nil, fmt.Errorf("%w: for %s", errContextNotFound, contextName)
+	}
+
+	cluster, found := config.Clusters[kubeContext.Cluster]
+	if !found {
+		return nil, fmt.Errorf("%w: for %s", errClusterNotFound, kubeContext.Cluster)
+	}
+
+	return cluster, nil
+}
+
+func apiServerURL(config clientcmdapi.Config, contextName string) (string, error) {
+	cluster, err := Cluster(config, contextName)
+	if err != nil {
+		return "", err
+	}
+
+	return cluster.Server, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"errors"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var errNoContext = errors.New("the kubeconfig returned for the managed cluster has no context")
+
+// RenameKubeconfig returns a kubeconfig with only the current context of the config (or its only context),
+// its cluster and its user, all named by the name
+func RenameKubeconfig(config *clientcmdapi.Config, name string) (*clientcmdapi.Config, error) {
+	kubeContext, found := config.Contexts[config.CurrentContext]
+	if !found && len(config.Contexts) == 1 {
+		for _, onlyContext := range config.Contexts {
+			kubeContext, found = onlyContext, true
+		}
+	}
+	if !found {
+		return nil, errNoContext
+	}
+
+	result := clientcmdapi.NewConfig()
+
+	if cluster, found := config.Clusters[kubeContext.Cluster]; found {
+		result.Clusters[name] = cluster
+	}
+	if authInfo, found := config.AuthInfos[kubeContext.AuthInfo]; found {
+		result.AuthInfos[name] = authInfo
+	}
+
+	renamedContext := kubeContext.DeepCopy()
+	renamedContext.Cluster, renamedContext.AuthInfo = name, name
+	result.Contexts[name] = renamedContext
+	result.CurrentContext = name
+
+	return result, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ManagedByAnnotation is the annotation of the objects reported by leaf hubs that holds the name of the leaf hub
+	ManagedByAnnotation = "hub-of-hubs.open-cluster-management.io/managed-by"
+	// LastStatusSyncAnnotation is the annotation of the objects reported by leaf hubs that holds the time
+	// the status of the object was last synced from the leaf hub, in RFC 3339 format
+	LastStatusSyncAnnotation = "hub-of-hubs.open-cluster-management.io/last-status-sync"
+)
+
+// GetLeafHubName returns the name of the leaf hub that reported the object, or an empty string if unknown
+func GetLeafHubName(obj metav1.Object) string {
+	return obj.GetAnnotations()[ManagedByAnnotation]
+}
+
+// GetLastStatusSync returns the time the status of the object was last synced from its leaf hub
+func GetLastStatusSync(obj metav1.Object) (time.Time, bool) {
+	lastStatusSync, found := obj.GetAnnotations()[LastStatusSyncAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+
+	parsed, err := time.Parse(time.RFC3339, lastStatusSync)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return parsed, true
+}
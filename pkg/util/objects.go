@@ -0,0 +1,45 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// GetObjects decodes a JSON array or a single JSON object returned by Non-K8s API into runtime objects
+func GetObjects(rawBytes []byte) ([]runtime.Object, error) {
+	var results []interface{}
+
+	err := json.Unmarshal(rawBytes, &results)
+	if err != nil {
+		var result interface{}
+		err := json.Unmarshal(rawBytes, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshall json: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	var objects []runtime.Object
+
+	for _, result := range results {
+		resultData, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshall json: %w", err)
+		}
+
+		converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, resultData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode: %w", err)
+		}
+
+		objects = append(objects, converted)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+const (
+	// NoneValue is the value printed for missing values, like kubectl does
+	NoneValue = "<none>"
+	// UnknownValue is the value printed for values that cannot be known, e.g. the age of an object without
+	// creation timestamp, like kubectl does
+	UnknownValue = "<unknown>"
+)
+
+// ValueOrNone returns the value, or NoneValue if the value is empty
+func ValueOrNone(value string) string {
+	if value == "" {
+		return NoneValue
+	}
+
+	return value
+}
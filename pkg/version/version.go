@@ -0,0 +1,8 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package version
+
+// Version is the version of the plugin, set at build time by
+// -ldflags "-X github.com/stolostron/hub-of-hubs-cli-plugins/pkg/version.Version=<version>"
+var Version = "unknown"